@@ -0,0 +1,469 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ConflictPolicy controls what ExecuteBatch does when an insert collides
+// with an existing row.
+type ConflictPolicy int
+
+const (
+	// ConflictAbort fails the individual insert (the default SQLite behavior).
+	ConflictAbort ConflictPolicy = iota
+	// ConflictIgnore silently skips rows that violate a uniqueness constraint.
+	ConflictIgnore
+	// ConflictReplace deletes the conflicting row and inserts the new one.
+	ConflictReplace
+	// ConflictUpdate merges the new values into the conflicting row via
+	// "ON CONFLICT(...) DO UPDATE SET ...", using BatchOptions.ConflictColumns
+	// as the conflict target.
+	ConflictUpdate
+)
+
+// BatchOptions configures ExecuteBatch.
+type BatchOptions struct {
+	// OnConflict selects how insert conflicts are resolved.
+	OnConflict ConflictPolicy
+	// ConflictColumns is the conflict target column list for
+	// ConflictUpdate, e.g. []string{"id"} or a unique composite key.
+	ConflictColumns []string
+	// MaxRows caps the total number of operations (inserts + updates +
+	// deletes) ExecuteBatch will attempt. Zero means unlimited.
+	MaxRows int
+}
+
+// RejectedOperation describes a single operation within a batch that failed
+// without aborting the rest of the batch.
+type RejectedOperation struct {
+	Kind  string // "insert", "update", or "delete"
+	Index int    // index into the corresponding BatchOperations slice
+	Err   error
+}
+
+// BatchResult summarizes the outcome of ExecuteBatch.
+type BatchResult struct {
+	InsertedRows int
+	UpdatedRows  int
+	DeletedRows  int
+	Rejected     []RejectedOperation
+}
+
+// column describes a single column's affinity as reported by
+// PRAGMA table_info, used to coerce JSON-decoded values (float64, string,
+// bool, nil, map, slice) to the Go type the sqlite3 driver expects for that
+// column's declared type.
+type column struct {
+	name     string
+	affinity string // one of "INTEGER", "REAL", "TEXT", "BLOB", "NUMERIC"
+}
+
+// sqliteAffinity maps a declared column type to one of SQLite's five type
+// affinities, following the rules in https://www.sqlite.org/datatype3.html#affinity.
+func sqliteAffinity(declaredType string) string {
+	t := strings.ToUpper(declaredType)
+	switch {
+	case strings.Contains(t, "INT"):
+		return "INTEGER"
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"):
+		return "TEXT"
+	case strings.Contains(t, "BLOB"), t == "":
+		return "BLOB"
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"):
+		return "REAL"
+	default:
+		return "NUMERIC"
+	}
+}
+
+// introspectColumns runs PRAGMA table_info(tableName) to discover the
+// table's columns and their affinities.
+func introspectColumns(ctx context.Context, db *sql.DB, tableName string) (map[string]column, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect table %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	columns := map[string]column{}
+	for rows.Next() {
+		var cid int
+		var name, declType string
+		var notNull, pk int
+		var dfltValue any
+		if err := rows.Scan(&cid, &name, &declType, &notNull, &dfltValue, &pk); err != nil {
+			return nil, fmt.Errorf("failed to scan table_info row: %w", err)
+		}
+		columns[name] = column{name: name, affinity: sqliteAffinity(declType)}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s has no columns (does it exist?)", tableName)
+	}
+	return columns, nil
+}
+
+// coerceValue converts a JSON-decoded value to the Go type matching col's
+// SQLite affinity, so e.g. a JSON number destined for an INTEGER column is
+// bound as int64 rather than float64.
+func coerceValue(v any, col column) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch col.affinity {
+	case "INTEGER":
+		switch n := v.(type) {
+		case float64:
+			return int64(n), nil
+		case int64:
+			return n, nil
+		case bool:
+			if n {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		}
+	case "REAL":
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case int64:
+			return float64(n), nil
+		}
+	case "TEXT":
+		switch s := v.(type) {
+		case string:
+			return s, nil
+		case map[string]any, []any:
+			data, err := json.Marshal(s)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode column %s as JSON text: %w", col.name, err)
+			}
+			return string(data), nil
+		}
+	}
+
+	// No coercion rule matched (NUMERIC affinity, or the value already has
+	// a type the driver accepts directly) - pass it through unchanged.
+	return v, nil
+}
+
+// ExecuteBatch validates and applies a BatchOperations payload against
+// tableName inside a single transaction, coercing JSON-decoded values to
+// the table's real column affinities and enforcing opts.OnConflict /
+// opts.MaxRows. Operations that fail individually (a bad WHERE clause, a
+// constraint violation under ConflictAbort, ...) are rolled back to a
+// per-operation savepoint and reported in BatchResult.Rejected rather than
+// aborting the whole batch.
+func ExecuteBatch(ctx context.Context, db *sql.DB, tableName string, ops BatchOperations, opts BatchOptions) (*BatchResult, error) {
+	if err := ValidateTableName(tableName); err != nil {
+		return nil, err
+	}
+
+	total := len(ops.Inserts) + len(ops.Updates) + len(ops.Deletes)
+	if opts.MaxRows > 0 && total > opts.MaxRows {
+		return nil, fmt.Errorf("batch of %d operations exceeds MaxRows limit of %d", total, opts.MaxRows)
+	}
+
+	columns, err := introspectColumns(ctx, db, tableName)
+	if err != nil {
+		return nil, err
+	}
+	allowedColumns := make(map[string]bool, len(columns))
+	for name := range columns {
+		allowedColumns[name] = true
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	result := &BatchResult{}
+
+	if len(ops.Inserts) > 0 {
+		if err := runGroup(ctx, conn, "inserts", func() error {
+			for i, op := range ops.Inserts {
+				if err := runOperation(ctx, conn, "insert", i, func() error {
+					n, err := execInsert(ctx, conn, tableName, op, columns, opts)
+					result.InsertedRows += n
+					return err
+				}, result); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			conn.ExecContext(ctx, "ROLLBACK")
+			return nil, err
+		}
+	}
+
+	if len(ops.Updates) > 0 {
+		if err := runGroup(ctx, conn, "updates", func() error {
+			for i, op := range ops.Updates {
+				if err := runOperation(ctx, conn, "update", i, func() error {
+					n, err := execUpdate(ctx, conn, tableName, op, columns, allowedColumns)
+					result.UpdatedRows += n
+					return err
+				}, result); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			conn.ExecContext(ctx, "ROLLBACK")
+			return nil, err
+		}
+	}
+
+	if len(ops.Deletes) > 0 {
+		if err := runGroup(ctx, conn, "deletes", func() error {
+			for i, op := range ops.Deletes {
+				if err := runOperation(ctx, conn, "delete", i, func() error {
+					n, err := execDelete(ctx, conn, tableName, op, allowedColumns)
+					result.DeletedRows += n
+					return err
+				}, result); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			conn.ExecContext(ctx, "ROLLBACK")
+			return nil, err
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return nil, fmt.Errorf("failed to commit batch: %w", err)
+	}
+
+	return result, nil
+}
+
+// runGroup wraps fn in a SAVEPOINT for one operation group (all inserts, all
+// updates, or all deletes), per the "savepoint per operation group" design.
+func runGroup(ctx context.Context, conn *sql.Conn, name string, fn func() error) error {
+	savepoint := "sp_" + name
+	if _, err := conn.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("failed to create savepoint for %s: %w", name, err)
+	}
+	if err := fn(); err != nil {
+		conn.ExecContext(ctx, "ROLLBACK TO "+savepoint)
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "RELEASE "+savepoint); err != nil {
+		return fmt.Errorf("failed to release savepoint for %s: %w", name, err)
+	}
+	return nil
+}
+
+// runOperation wraps a single operation in a nested savepoint so a failure
+// (bad WHERE clause, constraint violation under ConflictAbort, ...) can be
+// rolled back and recorded as rejected without losing the rest of the group.
+func runOperation(ctx context.Context, conn *sql.Conn, kind string, index int, fn func() error, result *BatchResult) error {
+	savepoint := fmt.Sprintf("op_%s_%d", kind, index)
+	if _, err := conn.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("failed to create operation savepoint: %w", err)
+	}
+
+	if err := fn(); err != nil {
+		if _, rbErr := conn.ExecContext(ctx, "ROLLBACK TO "+savepoint); rbErr != nil {
+			return fmt.Errorf("failed to roll back rejected %s operation: %w", kind, rbErr)
+		}
+		conn.ExecContext(ctx, "RELEASE "+savepoint)
+		result.Rejected = append(result.Rejected, RejectedOperation{Kind: kind, Index: index, Err: err})
+		return nil
+	}
+
+	if _, err := conn.ExecContext(ctx, "RELEASE "+savepoint); err != nil {
+		return fmt.Errorf("failed to release operation savepoint: %w", err)
+	}
+	return nil
+}
+
+func execInsert(ctx context.Context, conn *sql.Conn, tableName string, op InsertOperation, columns map[string]column, opts BatchOptions) (int, error) {
+	if len(op.Columns) == 0 {
+		return 0, fmt.Errorf("insert operation has no columns")
+	}
+
+	cols := make([]string, 0, len(op.Columns))
+	placeholders := make([]string, 0, len(op.Columns))
+	args := make([]any, 0, len(op.Columns))
+	for name, value := range op.Columns {
+		col, ok := columns[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown column %q", name)
+		}
+		coerced, err := coerceValue(value, col)
+		if err != nil {
+			return 0, err
+		}
+		cols = append(cols, name)
+		placeholders = append(placeholders, "?")
+		args = append(args, coerced)
+	}
+
+	insertVerb, err := insertVerbSQL(opts.OnConflict)
+	if err != nil {
+		return 0, err
+	}
+	conflictClause, err := conflictClauseSQL(opts, columns, cols)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("%s INTO %s (%s) VALUES (%s)%s",
+		insertVerb, tableName, strings.Join(cols, ", "), strings.Join(placeholders, ", "), conflictClause)
+
+	res, err := conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("insert failed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
+// insertVerbSQL returns the "INSERT"/"INSERT OR IGNORE"/"INSERT OR REPLACE"
+// statement prefix for opts.OnConflict. ConflictUpdate uses plain INSERT
+// plus an ON CONFLICT...DO UPDATE clause instead, built by conflictClauseSQL.
+func insertVerbSQL(policy ConflictPolicy) (string, error) {
+	switch policy {
+	case ConflictAbort, ConflictUpdate:
+		return "INSERT", nil
+	case ConflictIgnore:
+		return "INSERT OR IGNORE", nil
+	case ConflictReplace:
+		return "INSERT OR REPLACE", nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy %v", policy)
+	}
+}
+
+// conflictClauseSQL builds the "ON CONFLICT(...) DO UPDATE SET ..." clause
+// used by ConflictUpdate; every other policy is expressed via the INSERT
+// verb itself (see insertVerbSQL) and needs no trailing clause. insertCols is
+// the column list this particular insert is providing values for - the SET
+// clause is built from those, not the table's full column set (columns), so
+// columns the insert doesn't touch keep their existing value on a conflict
+// instead of being overwritten with excluded's default (NULL).
+func conflictClauseSQL(opts BatchOptions, columns map[string]column, insertCols []string) (string, error) {
+	switch opts.OnConflict {
+	case ConflictAbort, ConflictIgnore, ConflictReplace:
+		return "", nil
+	case ConflictUpdate:
+		if len(opts.ConflictColumns) == 0 {
+			return "", fmt.Errorf("OnConflict is ConflictUpdate but no ConflictColumns were given")
+		}
+		for _, c := range opts.ConflictColumns {
+			if _, ok := columns[c]; !ok {
+				return "", fmt.Errorf("unknown conflict column %q", c)
+			}
+		}
+		var setClauses []string
+		for _, name := range insertCols {
+			if containsString(opts.ConflictColumns, name) {
+				continue
+			}
+			setClauses = append(setClauses, fmt.Sprintf("%s = excluded.%s", name, name))
+		}
+		if len(setClauses) == 0 {
+			return "", fmt.Errorf("OnConflict is ConflictUpdate but every inserted column is a conflict target")
+		}
+		return fmt.Sprintf(" ON CONFLICT(%s) DO UPDATE SET %s",
+			strings.Join(opts.ConflictColumns, ", "), strings.Join(setClauses, ", ")), nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy %v", opts.OnConflict)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func execUpdate(ctx context.Context, conn *sql.Conn, tableName string, op UpdateOperation, columns map[string]column, allowedColumns map[string]bool) (int, error) {
+	merged := map[string]any{}
+	for _, set := range op.Set {
+		for k, v := range set {
+			merged[k] = v
+		}
+	}
+	if len(merged) == 0 {
+		return 0, fmt.Errorf("update operation has no columns to set")
+	}
+	if strings.TrimSpace(op.Where) == "" {
+		return 0, fmt.Errorf("update operation requires a WHERE clause")
+	}
+	if err := validateWhereClause(op.Where, allowedColumns); err != nil {
+		return 0, err
+	}
+
+	var setClauses []string
+	var args []any
+	for name, value := range merged {
+		col, ok := columns[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown column %q", name)
+		}
+		coerced, err := coerceValue(value, col)
+		if err != nil {
+			return 0, err
+		}
+		setClauses = append(setClauses, fmt.Sprintf("%s = ?", name))
+		args = append(args, coerced)
+	}
+
+	args = append(args, op.WhereArgs...)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", tableName, strings.Join(setClauses, ", "), op.Where)
+	res, err := conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("update failed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
+func execDelete(ctx context.Context, conn *sql.Conn, tableName string, op DeleteOperation, allowedColumns map[string]bool) (int, error) {
+	if strings.TrimSpace(op.Where) == "" {
+		return 0, fmt.Errorf("delete operation requires a WHERE clause")
+	}
+	if err := validateWhereClause(op.Where, allowedColumns); err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", tableName, op.Where)
+	res, err := conn.ExecContext(ctx, query, op.WhereArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("delete failed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rows affected: %w", err)
+	}
+	return int(n), nil
+}
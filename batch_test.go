@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func setupBatchTable(t *testing.T) *sql.DB {
+	t.Helper()
+	db := openTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE items(
+		id TEXT PRIMARY KEY,
+		name TEXT,
+		qty INTEGER NOT NULL
+	)`); err != nil {
+		t.Fatalf("failed to create items table: %v", err)
+	}
+	return db
+}
+
+// TestExecuteBatchConflictUpdatePreservesUntouchedColumns verifies that a
+// ConflictUpdate insert only overwrites the columns it actually provided,
+// leaving a conflicting row's other columns untouched rather than clobbered
+// to excluded's default (NULL).
+func TestExecuteBatchConflictUpdatePreservesUntouchedColumns(t *testing.T) {
+	db := setupBatchTable(t)
+	ctx := context.Background()
+
+	_, err := ExecuteBatch(ctx, db, "items", BatchOperations{
+		Inserts: []InsertOperation{
+			{Columns: map[string]any{"id": "1", "name": "widget", "qty": float64(5)}},
+		},
+	}, BatchOptions{})
+	if err != nil {
+		t.Fatalf("initial insert failed: %v", err)
+	}
+
+	// Conflicting insert only provides id/qty - name should survive.
+	_, err = ExecuteBatch(ctx, db, "items", BatchOperations{
+		Inserts: []InsertOperation{
+			{Columns: map[string]any{"id": "1", "qty": float64(9)}},
+		},
+	}, BatchOptions{OnConflict: ConflictUpdate, ConflictColumns: []string{"id"}})
+	if err != nil {
+		t.Fatalf("conflicting insert failed: %v", err)
+	}
+
+	var name string
+	var qty int
+	if err := db.QueryRowContext(ctx, "SELECT name, qty FROM items WHERE id = ?", "1").Scan(&name, &qty); err != nil {
+		t.Fatalf("failed to read back row: %v", err)
+	}
+	if name != "widget" {
+		t.Fatalf("expected name to survive the upsert untouched, got %q", name)
+	}
+	if qty != 9 {
+		t.Fatalf("expected qty to be updated to 9, got %d", qty)
+	}
+}
+
+// TestExecuteBatchWhereArgsBindPlaceholders verifies that `?` placeholders in
+// an UpdateOperation/DeleteOperation's Where clause - which validateWhereClause
+// explicitly allows - are actually bound from WhereArgs instead of causing a
+// parameter-count mismatch at exec time.
+func TestExecuteBatchWhereArgsBindPlaceholders(t *testing.T) {
+	db := setupBatchTable(t)
+	ctx := context.Background()
+
+	if _, err := ExecuteBatch(ctx, db, "items", BatchOperations{
+		Inserts: []InsertOperation{
+			{Columns: map[string]any{"id": "1", "name": "widget", "qty": float64(5)}},
+			{Columns: map[string]any{"id": "2", "name": "gadget", "qty": float64(1)}},
+		},
+	}, BatchOptions{}); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+
+	result, err := ExecuteBatch(ctx, db, "items", BatchOperations{
+		Updates: []UpdateOperation{
+			{Set: []map[string]any{{"qty": float64(42)}}, Where: "id = ?", WhereArgs: []any{"1"}},
+		},
+	}, BatchOptions{})
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if result.UpdatedRows != 1 {
+		t.Fatalf("expected 1 updated row, got %d", result.UpdatedRows)
+	}
+
+	result, err = ExecuteBatch(ctx, db, "items", BatchOperations{
+		Deletes: []DeleteOperation{
+			{Where: "id = ?", WhereArgs: []any{"2"}},
+		},
+	}, BatchOptions{})
+	if err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if result.DeletedRows != 1 {
+		t.Fatalf("expected 1 deleted row, got %d", result.DeletedRows)
+	}
+
+	var remaining int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM items").Scan(&remaining); err != nil {
+		t.Fatalf("failed to count remaining rows: %v", err)
+	}
+	if remaining != 1 {
+		t.Fatalf("expected 1 remaining row, got %d", remaining)
+	}
+}
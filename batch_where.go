@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// whereAllowedKeywords is the fixed set of keywords permitted in a WHERE
+// clause passed to ExecuteBatch. Anything else - comments, statement
+// separators, function calls, subqueries - is rejected.
+var whereAllowedKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true,
+	"IS": true, "NULL": true, "IN": true, "LIKE": true,
+	"TRUE": true, "FALSE": true,
+}
+
+// validateWhereClause checks that where references only columns in
+// allowedColumns, using only the fixed operator/keyword set above, with no
+// statement separators or comments. It does not fully parse operator
+// precedence or arity - that's SQLite's job once the clause is known safe -
+// it only rejects anything that isn't an identifier, literal, parameter
+// placeholder, or allowed punctuation/keyword, which is exactly what's
+// needed to stop an LLM-generated string from smuggling in injected SQL.
+func validateWhereClause(where string, allowedColumns map[string]bool) error {
+	runes := []rune(where)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			i++
+		case r == '?':
+			i++
+		case r == '=' || r == '<' || r == '>':
+			i++
+			if i < len(runes) && (runes[i] == '=' || (r == '<' && runes[i] == '>')) {
+				i++
+			}
+		case r == '!':
+			if i+1 >= len(runes) || runes[i+1] != '=' {
+				return fmt.Errorf("where clause: unexpected character %q", r)
+			}
+			i += 2
+		case r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != '\'' {
+				j++
+			}
+			if j >= len(runes) {
+				return fmt.Errorf("where clause: unterminated string literal")
+			}
+			i = j + 1
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			word := string(runes[i:j])
+			upper := strings.ToUpper(word)
+			if !whereAllowedKeywords[upper] && !allowedColumns[word] {
+				return fmt.Errorf("where clause: column or keyword %q is not recognized", word)
+			}
+			i = j
+		default:
+			return fmt.Errorf("where clause: disallowed character %q", r)
+		}
+	}
+
+	return nil
+}
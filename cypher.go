@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// Query parses and executes a pragmatic subset of openCypher against the
+// graph: MATCH with node patterns ("(n:Label {prop: $param})") and
+// relationship patterns ("-[r:TYPE*1..3]->"), an optional WHERE clause, and
+// a RETURN projection of nodes, relationships, or property expressions.
+//
+// Relationship traversal (including variable-length "*min..max" hops) is
+// compiled into a single SQL statement using recursive CTEs against the
+// backing nodes/edges tables; label filters are pushed into that SQL as a
+// cheap substring test, while property-map and WHERE predicates are
+// evaluated in Go against the decoded JSON properties, consistent with how
+// FindNodes/FindRelationships already filter. The returned Result streams
+// rows from the underlying *sql.Rows rather than materializing the whole
+// result set.
+func (g *GraphDB) Query(ctx context.Context, cypher string, params map[string]any) (*Result, error) {
+	query, err := parseCypher(cypher)
+	if err != nil {
+		return nil, fmt.Errorf("cypher: failed to parse query: %w", err)
+	}
+
+	plan, err := planQuery(g, query)
+	if err != nil {
+		return nil, fmt.Errorf("cypher: failed to plan query: %w", err)
+	}
+
+	rows, err := g.db.QueryContext(ctx, plan.sql, plan.args...)
+	if err != nil {
+		return nil, fmt.Errorf("cypher: failed to execute query: %w", err)
+	}
+
+	if params == nil {
+		params = map[string]any{}
+	}
+
+	return &Result{
+		ctx:    ctx,
+		g:      g,
+		rows:   rows,
+		plan:   plan,
+		query:  query,
+		params: params,
+	}, nil
+}
+
+// relationshipByID fetches a single edge row by id, used to hydrate
+// variable-length path variables after a recursive CTE traversal.
+func (g *GraphDB) relationshipByID(ctx context.Context, id int64) (*Relationship, error) {
+	query := fmt.Sprintf("SELECT id, source, target, edge_type, weight, properties FROM %s WHERE id = ?", g.edgesTable)
+	row := g.db.QueryRowContext(ctx, query, id)
+
+	var relID, source, target int64
+	var edgeType, propertiesJSON string
+	var weight float64
+	if err := row.Scan(&relID, &source, &target, &edgeType, &weight, &propertiesJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("cypher: relationship %d referenced by path no longer exists", id)
+		}
+		return nil, fmt.Errorf("cypher: failed to load relationship %d: %w", id, err)
+	}
+
+	props := map[string]any{}
+	if propertiesJSON != "" {
+		if err := json.Unmarshal([]byte(propertiesJSON), &props); err != nil {
+			return nil, fmt.Errorf("cypher: failed to decode relationship properties: %w", err)
+		}
+	}
+
+	return &Relationship{ID: relID, StartNode: source, EndNode: target, Type: edgeType, Properties: props}, nil
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+// This file and its cypher_lexer.go / cypher_parser.go / cypher_plan.go
+// siblings implement a small, pragmatic subset of openCypher against the
+// GraphDB backing tables. It is not a general-purpose Cypher engine: it
+// supports exactly the constructs GraphDB.Query documents (single MATCH,
+// optional WHERE, RETURN of variables/properties), which covers the
+// "friend-of-friend" style traversal queries this package is used for.
+
+// Direction describes which way a relationship pattern points.
+type Direction int
+
+const (
+	DirRight  Direction = iota // (a)-[r]->(b)
+	DirLeft                    // (a)<-[r]-(b)
+	DirEither                  // (a)-[r]-(b)
+)
+
+// NodePattern is a single "(var:Label1:Label2 {prop: expr, ...})" pattern.
+type NodePattern struct {
+	Variable   string
+	Labels     []string
+	Properties map[string]Expr
+}
+
+// RelPattern is a single "-[var:TYPE1|TYPE2*min..max]->" pattern.
+type RelPattern struct {
+	Variable   string
+	Types      []string
+	Direction  Direction
+	Properties map[string]Expr
+	MinHops    int
+	MaxHops    int
+}
+
+// PatternElement is one node-relationship-node step of a MATCH pattern
+// chain: the relationship and the node it leads to. The first node of a
+// chain is stored separately on MatchClause.Start.
+type PatternElement struct {
+	Rel  RelPattern
+	Node NodePattern
+}
+
+// MatchClause is a single "MATCH (start)-[rel]->(node)-..." pattern chain.
+type MatchClause struct {
+	Start    NodePattern
+	Elements []PatternElement
+}
+
+// ReturnItem is one expression in a RETURN clause, e.g. "n", "n.name", or
+// "n.name AS name".
+type ReturnItem struct {
+	Expr  Expr
+	Alias string
+}
+
+// Query is the parsed form of a single Cypher statement understood by
+// GraphDB.Query: "MATCH <pattern> [WHERE <expr>] RETURN <items>".
+type Query struct {
+	Match  MatchClause
+	Where  Expr // nil if no WHERE clause
+	Return []ReturnItem
+}
+
+// Expr is implemented by every node in a WHERE/RETURN expression tree.
+type Expr interface {
+	exprNode()
+}
+
+// Literal is a constant value: string, number, bool, or nil.
+type Literal struct {
+	Value any
+}
+
+// Parameter is a "$name" reference into the params map passed to Query.
+type Parameter struct {
+	Name string
+}
+
+// VarRef is a bare variable reference, e.g. the "n" in "RETURN n".
+type VarRef struct {
+	Name string
+}
+
+// PropertyAccess is "variable.property", e.g. "n.name".
+type PropertyAccess struct {
+	Variable string
+	Property string
+}
+
+// UnaryExpr is "NOT expr".
+type UnaryExpr struct {
+	Op      string
+	Operand Expr
+}
+
+// BinaryExpr is "left OP right" for both boolean connectives (AND/OR) and
+// comparison operators (=, <>, <, <=, >, >=).
+type BinaryExpr struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+func (Literal) exprNode()        {}
+func (Parameter) exprNode()      {}
+func (VarRef) exprNode()         {}
+func (PropertyAccess) exprNode() {}
+func (UnaryExpr) exprNode()      {}
+func (BinaryExpr) exprNode()     {}
@@ -0,0 +1,148 @@
+package sqlite
+
+import "fmt"
+
+// evalExpr evaluates a WHERE/RETURN expression against the current row's
+// bound nodes and relationships plus the query's parameter map. nodes/rels
+// may be nil when evaluating a pattern-property expression that can only
+// reference parameters (see propertiesMatch).
+func evalExpr(e Expr, nodes map[string]*Node, rels map[string]*Relationship, params map[string]any) (any, error) {
+	switch v := e.(type) {
+	case Literal:
+		return v.Value, nil
+	case Parameter:
+		val, ok := params[v.Name]
+		if !ok {
+			return nil, fmt.Errorf("cypher: missing parameter $%s", v.Name)
+		}
+		return val, nil
+	case VarRef:
+		if n, ok := nodes[v.Name]; ok {
+			return n, nil
+		}
+		if r, ok := rels[v.Name]; ok {
+			return r, nil
+		}
+		return nil, fmt.Errorf("cypher: unbound variable %q", v.Name)
+	case PropertyAccess:
+		if n, ok := nodes[v.Variable]; ok {
+			return n.Properties[v.Property], nil
+		}
+		if r, ok := rels[v.Variable]; ok {
+			return r.Properties[v.Property], nil
+		}
+		return nil, fmt.Errorf("cypher: unbound variable %q in property access", v.Variable)
+	case UnaryExpr:
+		val, err := evalExpr(v.Operand, nodes, rels, params)
+		if err != nil {
+			return nil, err
+		}
+		b, _ := val.(bool)
+		if v.Op == "NOT" {
+			return !b, nil
+		}
+		return nil, fmt.Errorf("cypher: unsupported unary operator %q", v.Op)
+	case BinaryExpr:
+		return evalBinary(v, nodes, rels, params)
+	default:
+		return nil, fmt.Errorf("cypher: unsupported expression %T", e)
+	}
+}
+
+func evalBinary(b BinaryExpr, nodes map[string]*Node, rels map[string]*Relationship, params map[string]any) (any, error) {
+	switch b.Op {
+	case "AND", "OR":
+		left, err := evalExpr(b.Left, nodes, rels, params)
+		if err != nil {
+			return nil, err
+		}
+		lb, _ := left.(bool)
+		if b.Op == "AND" && !lb {
+			return false, nil
+		}
+		if b.Op == "OR" && lb {
+			return true, nil
+		}
+		right, err := evalExpr(b.Right, nodes, rels, params)
+		if err != nil {
+			return nil, err
+		}
+		rb, _ := right.(bool)
+		return rb, nil
+	default:
+		left, err := evalExpr(b.Left, nodes, rels, params)
+		if err != nil {
+			return nil, err
+		}
+		right, err := evalExpr(b.Right, nodes, rels, params)
+		if err != nil {
+			return nil, err
+		}
+		return compare(b.Op, left, right)
+	}
+}
+
+func compare(op string, left, right any) (bool, error) {
+	if op == "=" {
+		return valuesEqual(left, right), nil
+	}
+	if op == "<>" {
+		return !valuesEqual(left, right), nil
+	}
+
+	lf, lok := asFloat(left)
+	rf, rok := asFloat(right)
+	if lok && rok {
+		switch op {
+		case "<":
+			return lf < rf, nil
+		case "<=":
+			return lf <= rf, nil
+		case ">":
+			return lf > rf, nil
+		case ">=":
+			return lf >= rf, nil
+		}
+	}
+
+	ls, lok := left.(string)
+	rs, rok := right.(string)
+	if lok && rok {
+		switch op {
+		case "<":
+			return ls < rs, nil
+		case "<=":
+			return ls <= rs, nil
+		case ">":
+			return ls > rs, nil
+		case ">=":
+			return ls >= rs, nil
+		}
+	}
+
+	return false, fmt.Errorf("cypher: cannot compare %v %s %v", left, op, right)
+}
+
+func asFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func valuesEqual(a, b any) bool {
+	if af, aok := asFloat(a); aok {
+		if bf, bok := asFloat(b); bok {
+			return af == bf
+		}
+	}
+	return a == b
+}
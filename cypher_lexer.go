@@ -0,0 +1,255 @@
+package sqlite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokParam
+	tokString
+	tokNumber
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokLBrace
+	tokRBrace
+	tokColon
+	tokComma
+	tokDot
+	tokDotDot
+	tokDash
+	tokArrowRight // ->
+	tokArrowLeft  // <-
+	tokStar
+	tokPipe
+	tokEq
+	tokNeq
+	tokLt
+	tokLe
+	tokGt
+	tokGe
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+}
+
+// cypherLexer tokenizes a pragmatic subset of openCypher: enough for
+// MATCH/WHERE/RETURN with node and relationship patterns.
+type cypherLexer struct {
+	src []rune
+	pos int
+}
+
+func newCypherLexer(src string) *cypherLexer {
+	return &cypherLexer{src: []rune(src)}
+}
+
+func (l *cypherLexer) peekRune() (rune, bool) {
+	if l.pos >= len(l.src) {
+		return 0, false
+	}
+	return l.src[l.pos], true
+}
+
+func (l *cypherLexer) skipSpace() {
+	for l.pos < len(l.src) && unicode.IsSpace(l.src[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *cypherLexer) next() (token, error) {
+	l.skipSpace()
+	r, ok := l.peekRune()
+	if !ok {
+		return token{kind: tokEOF}, nil
+	}
+
+	switch {
+	case r == '(':
+		l.pos++
+		return token{kind: tokLParen}, nil
+	case r == ')':
+		l.pos++
+		return token{kind: tokRParen}, nil
+	case r == '[':
+		l.pos++
+		return token{kind: tokLBracket}, nil
+	case r == ']':
+		l.pos++
+		return token{kind: tokRBracket}, nil
+	case r == '{':
+		l.pos++
+		return token{kind: tokLBrace}, nil
+	case r == '}':
+		l.pos++
+		return token{kind: tokRBrace}, nil
+	case r == ':':
+		l.pos++
+		return token{kind: tokColon}, nil
+	case r == ',':
+		l.pos++
+		return token{kind: tokComma}, nil
+	case r == '|':
+		l.pos++
+		return token{kind: tokPipe}, nil
+	case r == '*':
+		l.pos++
+		return token{kind: tokStar}, nil
+	case r == '.':
+		l.pos++
+		if p, ok := l.peekRune(); ok && p == '.' {
+			l.pos++
+			return token{kind: tokDotDot}, nil
+		}
+		return token{kind: tokDot}, nil
+	case r == '-':
+		l.pos++
+		if p, ok := l.peekRune(); ok && p == '>' {
+			l.pos++
+			return token{kind: tokArrowRight}, nil
+		}
+		return token{kind: tokDash}, nil
+	case r == '<':
+		l.pos++
+		if p, ok := l.peekRune(); ok {
+			if p == '-' {
+				l.pos++
+				return token{kind: tokArrowLeft}, nil
+			}
+			if p == '>' {
+				l.pos++
+				return token{kind: tokNeq}, nil
+			}
+			if p == '=' {
+				l.pos++
+				return token{kind: tokLe}, nil
+			}
+		}
+		return token{kind: tokLt}, nil
+	case r == '>':
+		l.pos++
+		if p, ok := l.peekRune(); ok && p == '=' {
+			l.pos++
+			return token{kind: tokGe}, nil
+		}
+		return token{kind: tokGt}, nil
+	case r == '=':
+		l.pos++
+		return token{kind: tokEq}, nil
+	case r == '$':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.src) && isIdentRune(l.src[l.pos]) {
+			l.pos++
+		}
+		if l.pos == start {
+			return token{}, fmt.Errorf("cypher: expected parameter name after '$' at position %d", start)
+		}
+		return token{kind: tokParam, text: string(l.src[start:l.pos])}, nil
+	case r == '\'' || r == '"':
+		return l.lexString(r)
+	case unicode.IsDigit(r):
+		return l.lexNumber()
+	case isIdentStartRune(r):
+		return l.lexIdentOrKeyword()
+	default:
+		return token{}, fmt.Errorf("cypher: unexpected character %q at position %d", r, l.pos)
+	}
+}
+
+func isIdentStartRune(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func (l *cypherLexer) lexString(quote rune) (token, error) {
+	l.pos++ // consume opening quote
+	var sb strings.Builder
+	for {
+		r, ok := l.peekRune()
+		if !ok {
+			return token{}, fmt.Errorf("cypher: unterminated string literal")
+		}
+		if r == quote {
+			l.pos++
+			return token{kind: tokString, text: sb.String()}, nil
+		}
+		if r == '\\' {
+			l.pos++
+			if esc, ok := l.peekRune(); ok {
+				sb.WriteRune(esc)
+				l.pos++
+				continue
+			}
+			return token{}, fmt.Errorf("cypher: unterminated escape in string literal")
+		}
+		sb.WriteRune(r)
+		l.pos++
+	}
+}
+
+func (l *cypherLexer) lexNumber() (token, error) {
+	start := l.pos
+	sawDot := false
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if unicode.IsDigit(c) {
+			l.pos++
+			continue
+		}
+		// A lone "." is this number's decimal point; ".." is the
+		// variable-length-hop range operator ("*1..2") and must be left
+		// for the lexer to tokenize as tokDotDot, not swallowed here.
+		if c == '.' && !sawDot && l.pos+1 < len(l.src) && l.src[l.pos+1] != '.' {
+			sawDot = true
+			l.pos++
+			continue
+		}
+		break
+	}
+	text := string(l.src[start:l.pos])
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return token{}, fmt.Errorf("cypher: invalid number literal %q: %w", text, err)
+	}
+	return token{kind: tokNumber, text: text, num: n}, nil
+}
+
+func (l *cypherLexer) lexIdentOrKeyword() (token, error) {
+	start := l.pos
+	for l.pos < len(l.src) && isIdentRune(l.src[l.pos]) {
+		l.pos++
+	}
+	return token{kind: tokIdent, text: string(l.src[start:l.pos])}, nil
+}
+
+// tokenize runs the lexer to completion, returning the full token stream
+// terminated by a tokEOF.
+func tokenize(src string) ([]token, error) {
+	l := newCypherLexer(src)
+	var tokens []token
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokEOF {
+			return tokens, nil
+		}
+	}
+}
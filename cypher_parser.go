@@ -0,0 +1,450 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cypherParser is a small recursive-descent parser over the token stream
+// produced by tokenize. It implements just enough of openCypher's grammar
+// to cover MATCH/WHERE/RETURN as documented on GraphDB.Query.
+type cypherParser struct {
+	tokens []token
+	pos    int
+}
+
+func parseCypher(src string) (*Query, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &cypherParser{tokens: tokens}
+	return p.parseQuery()
+}
+
+func (p *cypherParser) cur() token {
+	return p.tokens[p.pos]
+}
+
+func (p *cypherParser) advance() token {
+	t := p.tokens[p.pos]
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return t
+}
+
+func (p *cypherParser) expect(kind tokenKind, what string) (token, error) {
+	if p.cur().kind != kind {
+		return token{}, fmt.Errorf("cypher: expected %s, got %v", what, p.describeCur())
+	}
+	return p.advance(), nil
+}
+
+func (p *cypherParser) describeCur() string {
+	t := p.cur()
+	if t.kind == tokEOF {
+		return "end of input"
+	}
+	if t.text != "" {
+		return fmt.Sprintf("%q", t.text)
+	}
+	return "token"
+}
+
+// keyword matches a case-insensitive identifier keyword (MATCH, WHERE, ...)
+// without consuming it; use consumeKeyword to advance past it.
+func (p *cypherParser) keywordIs(kw string) bool {
+	t := p.cur()
+	return t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+func (p *cypherParser) consumeKeyword(kw string) error {
+	if !p.keywordIs(kw) {
+		return fmt.Errorf("cypher: expected keyword %q, got %s", kw, p.describeCur())
+	}
+	p.advance()
+	return nil
+}
+
+func (p *cypherParser) parseQuery() (*Query, error) {
+	if err := p.consumeKeyword("MATCH"); err != nil {
+		return nil, err
+	}
+
+	match, err := p.parseMatchClause()
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{Match: match}
+
+	if p.keywordIs("WHERE") {
+		p.advance()
+		where, err := p.parseOrExpr()
+		if err != nil {
+			return nil, err
+		}
+		q.Where = where
+	}
+
+	if err := p.consumeKeyword("RETURN"); err != nil {
+		return nil, err
+	}
+
+	items, err := p.parseReturnItems()
+	if err != nil {
+		return nil, err
+	}
+	q.Return = items
+
+	if p.cur().kind != tokEOF {
+		return nil, fmt.Errorf("cypher: unexpected trailing input at %s", p.describeCur())
+	}
+
+	return q, nil
+}
+
+func (p *cypherParser) parseMatchClause() (MatchClause, error) {
+	start, err := p.parseNodePattern()
+	if err != nil {
+		return MatchClause{}, err
+	}
+
+	clause := MatchClause{Start: start}
+	for p.cur().kind == tokDash || p.cur().kind == tokArrowLeft {
+		rel, err := p.parseRelPattern()
+		if err != nil {
+			return MatchClause{}, err
+		}
+		node, err := p.parseNodePattern()
+		if err != nil {
+			return MatchClause{}, err
+		}
+		clause.Elements = append(clause.Elements, PatternElement{Rel: rel, Node: node})
+	}
+
+	return clause, nil
+}
+
+func (p *cypherParser) parseNodePattern() (NodePattern, error) {
+	if _, err := p.expect(tokLParen, "'('"); err != nil {
+		return NodePattern{}, err
+	}
+
+	var n NodePattern
+	if p.cur().kind == tokIdent && !p.keywordIs("AS") {
+		n.Variable = p.advance().text
+	}
+
+	for p.cur().kind == tokColon {
+		p.advance()
+		label, err := p.expect(tokIdent, "label")
+		if err != nil {
+			return NodePattern{}, err
+		}
+		n.Labels = append(n.Labels, label.text)
+	}
+
+	if p.cur().kind == tokLBrace {
+		props, err := p.parsePropertyMap()
+		if err != nil {
+			return NodePattern{}, err
+		}
+		n.Properties = props
+	}
+
+	if _, err := p.expect(tokRParen, "')'"); err != nil {
+		return NodePattern{}, err
+	}
+	return n, nil
+}
+
+func (p *cypherParser) parseRelPattern() (RelPattern, error) {
+	rel := RelPattern{MinHops: 1, MaxHops: 1, Direction: DirRight}
+
+	leftArrow := false
+	if p.cur().kind == tokArrowLeft {
+		leftArrow = true
+		p.advance()
+	} else {
+		if _, err := p.expect(tokDash, "'-'"); err != nil {
+			return RelPattern{}, err
+		}
+	}
+
+	if p.cur().kind == tokLBracket {
+		p.advance()
+
+		if p.cur().kind == tokIdent && !p.keywordIs("AS") {
+			rel.Variable = p.advance().text
+		}
+
+		if p.cur().kind == tokColon {
+			p.advance()
+			typ, err := p.expect(tokIdent, "relationship type")
+			if err != nil {
+				return RelPattern{}, err
+			}
+			rel.Types = append(rel.Types, typ.text)
+			for p.cur().kind == tokPipe {
+				p.advance()
+				typ, err := p.expect(tokIdent, "relationship type")
+				if err != nil {
+					return RelPattern{}, err
+				}
+				rel.Types = append(rel.Types, typ.text)
+			}
+		}
+
+		if p.cur().kind == tokStar {
+			p.advance()
+			rel.MinHops, rel.MaxHops = 1, 0 // 0 sentinel means "unbounded" until we see explicit numbers
+			rel.MaxHops = -1
+			if p.cur().kind == tokNumber {
+				rel.MinHops = int(p.advance().num)
+				rel.MaxHops = rel.MinHops
+			}
+			if p.cur().kind == tokDotDot {
+				p.advance()
+				if p.cur().kind == tokNumber {
+					rel.MaxHops = int(p.advance().num)
+				} else {
+					rel.MaxHops = cypherMaxVariableHops
+				}
+			}
+			if rel.MaxHops == -1 {
+				rel.MaxHops = cypherMaxVariableHops
+			}
+		}
+
+		if p.cur().kind == tokLBrace {
+			props, err := p.parsePropertyMap()
+			if err != nil {
+				return RelPattern{}, err
+			}
+			rel.Properties = props
+		}
+
+		if _, err := p.expect(tokRBracket, "']'"); err != nil {
+			return RelPattern{}, err
+		}
+	}
+
+	switch p.cur().kind {
+	case tokArrowRight:
+		p.advance()
+		if leftArrow {
+			return RelPattern{}, fmt.Errorf("cypher: relationship pattern cannot point both directions")
+		}
+		rel.Direction = DirRight
+	case tokDash:
+		p.advance()
+		if leftArrow {
+			rel.Direction = DirLeft
+		} else {
+			rel.Direction = DirEither
+		}
+	default:
+		return RelPattern{}, fmt.Errorf("cypher: expected '-' or '->' to close relationship pattern, got %s", p.describeCur())
+	}
+
+	return rel, nil
+}
+
+// cypherMaxVariableHops bounds unbounded variable-length patterns like
+// "*1.." so the generated recursive CTE cannot run away indefinitely.
+const cypherMaxVariableHops = 16
+
+func (p *cypherParser) parsePropertyMap() (map[string]Expr, error) {
+	if _, err := p.expect(tokLBrace, "'{'"); err != nil {
+		return nil, err
+	}
+	props := map[string]Expr{}
+	if p.cur().kind == tokRBrace {
+		p.advance()
+		return props, nil
+	}
+	for {
+		key, err := p.expect(tokIdent, "property name")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokColon, "':'"); err != nil {
+			return nil, err
+		}
+		value, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		props[key.text] = value
+
+		if p.cur().kind == tokComma {
+			p.advance()
+			continue
+		}
+		break
+	}
+	if _, err := p.expect(tokRBrace, "'}'"); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+func (p *cypherParser) parseReturnItems() ([]ReturnItem, error) {
+	var items []ReturnItem
+	for {
+		expr, err := p.parsePrimaryOrPropertyExpr()
+		if err != nil {
+			return nil, err
+		}
+		item := ReturnItem{Expr: expr}
+		if p.keywordIs("AS") {
+			p.advance()
+			alias, err := p.expect(tokIdent, "alias")
+			if err != nil {
+				return nil, err
+			}
+			item.Alias = alias.text
+		}
+		items = append(items, item)
+
+		if p.cur().kind == tokComma {
+			p.advance()
+			continue
+		}
+		break
+	}
+	return items, nil
+}
+
+// parsePrimaryOrPropertyExpr parses a RETURN item expression: a bare
+// variable or a "var.prop" property access.
+func (p *cypherParser) parsePrimaryOrPropertyExpr() (Expr, error) {
+	return p.parsePrimary()
+}
+
+func (p *cypherParser) parseOrExpr() (Expr, error) {
+	left, err := p.parseAndExpr()
+	if err != nil {
+		return nil, err
+	}
+	for p.keywordIs("OR") {
+		p.advance()
+		right, err := p.parseAndExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: "OR", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *cypherParser) parseAndExpr() (Expr, error) {
+	left, err := p.parseNotExpr()
+	if err != nil {
+		return nil, err
+	}
+	for p.keywordIs("AND") {
+		p.advance()
+		right, err := p.parseNotExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: "AND", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *cypherParser) parseNotExpr() (Expr, error) {
+	if p.keywordIs("NOT") {
+		p.advance()
+		operand, err := p.parseNotExpr()
+		if err != nil {
+			return nil, err
+		}
+		return UnaryExpr{Op: "NOT", Operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *cypherParser) parseComparison() (Expr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	op := ""
+	switch p.cur().kind {
+	case tokEq:
+		op = "="
+	case tokNeq:
+		op = "<>"
+	case tokLt:
+		op = "<"
+	case tokLe:
+		op = "<="
+	case tokGt:
+		op = ">"
+	case tokGe:
+		op = ">="
+	default:
+		return left, nil
+	}
+	p.advance()
+
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return BinaryExpr{Op: op, Left: left, Right: right}, nil
+}
+
+func (p *cypherParser) parsePrimary() (Expr, error) {
+	t := p.cur()
+	switch t.kind {
+	case tokLParen:
+		p.advance()
+		expr, err := p.parseOrExpr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case tokParam:
+		p.advance()
+		return Parameter{Name: t.text}, nil
+	case tokString:
+		p.advance()
+		return Literal{Value: t.text}, nil
+	case tokNumber:
+		p.advance()
+		return Literal{Value: t.num}, nil
+	case tokIdent:
+		switch strings.ToLower(t.text) {
+		case "true":
+			p.advance()
+			return Literal{Value: true}, nil
+		case "false":
+			p.advance()
+			return Literal{Value: false}, nil
+		case "null":
+			p.advance()
+			return Literal{Value: nil}, nil
+		}
+		p.advance()
+		if p.cur().kind == tokDot {
+			p.advance()
+			prop, err := p.expect(tokIdent, "property name")
+			if err != nil {
+				return nil, err
+			}
+			return PropertyAccess{Variable: t.text, Property: prop.text}, nil
+		}
+		return VarRef{Name: t.text}, nil
+	default:
+		return nil, fmt.Errorf("cypher: unexpected token %s in expression", p.describeCur())
+	}
+}
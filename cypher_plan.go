@@ -0,0 +1,327 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+)
+
+// varKind distinguishes a bound pattern variable that denotes a single node,
+// a single relationship (fixed-length hop), or a variable-length path of
+// relationships.
+type varKind int
+
+const (
+	varKindNode varKind = iota
+	varKindRel
+	varKindPath
+)
+
+// varBinding records where, in the generated SQL's column list, a bound
+// pattern variable's data lives.
+type varBinding struct {
+	kind    varKind
+	pattern NodePattern // set when kind == varKindNode
+	relPat  RelPattern  // set when kind == varKindRel || kind == varKindPath
+	// cols are the SELECT column aliases (in scan order) carrying this
+	// variable's data: node -> {id, labels, properties};
+	// rel -> {id, source, target, type, weight, properties};
+	// path -> {path_edges}.
+	cols []string
+}
+
+// cypherPlan is the compiled form of a Query: the SQL to run, the order in
+// which its columns should be scanned, and enough metadata to re-apply
+// pattern/WHERE filtering that isn't pushed into SQL (see the package-level
+// comment in cypher_ast.go for why property predicates are evaluated in Go
+// rather than against the JSON-encoded properties column).
+type cypherPlan struct {
+	sql      string
+	args     []any
+	scanCols []string // flat, in the exact order they appear in the SELECT list
+	vars     map[string]varBinding
+	order    []string // variable names in pattern order, for deterministic iteration
+}
+
+// planQuery compiles a parsed Query into SQL against g's backing tables.
+func planQuery(g *GraphDB, q *Query) (*cypherPlan, error) {
+	vars := map[string]varBinding{}
+	var order []string
+	var scanCols []string
+	var selectExprs []string
+	// cteArgs and fromArgs are kept separate, then concatenated at the end
+	// in cteArgs-then-fromArgs order: the final SQL always renders every
+	// CTE (the WITH clause) before the FROM clause, so a single args slice
+	// appended to in construction order would drift out of sync with the
+	// placeholders' left-to-right order in the text as soon as a FROM-clause
+	// join (e.g. a relationship type filter) sits between two CTEs.
+	var cteArgs []any
+	var fromArgs []any
+
+	bind := func(name string, b varBinding) {
+		if name == "" {
+			return
+		}
+		if _, exists := vars[name]; !exists {
+			order = append(order, name)
+		}
+		vars[name] = b
+	}
+
+	addNodeSelect := func(alias, varName string, pattern NodePattern) {
+		cols := []string{alias + ".id", alias + ".labels", alias + ".properties"}
+		aliased := []string{
+			fmt.Sprintf("%s AS %s_id", cols[0], alias),
+			fmt.Sprintf("%s AS %s_labels", cols[1], alias),
+			fmt.Sprintf("%s AS %s_properties", cols[2], alias),
+		}
+		selectExprs = append(selectExprs, aliased...)
+		scanCols = append(scanCols, alias+"_id", alias+"_labels", alias+"_properties")
+		if varName != "" {
+			bind(varName, varBinding{kind: varKindNode, pattern: pattern, cols: []string{alias + "_id", alias + "_labels", alias + "_properties"}})
+		}
+	}
+
+	addRelSelect := func(alias, varName string, pat RelPattern) {
+		fields := []string{"id", "source", "target", "edge_type", "weight", "properties"}
+		for _, f := range fields {
+			selectExprs = append(selectExprs, fmt.Sprintf("%s.%s AS %s_%s", alias, f, alias, f))
+			scanCols = append(scanCols, alias+"_"+f)
+		}
+		if varName != "" {
+			cols := make([]string, len(fields))
+			for i, f := range fields {
+				cols[i] = alias + "_" + f
+			}
+			bind(varName, varBinding{kind: varKindRel, relPat: pat, cols: cols})
+		}
+	}
+
+	addPathSelect := func(alias, varName string, pat RelPattern) {
+		selectExprs = append(selectExprs, fmt.Sprintf("%s.path_edges AS %s_path_edges", alias, alias))
+		scanCols = append(scanCols, alias+"_path_edges")
+		if varName != "" {
+			bind(varName, varBinding{kind: varKindPath, relPat: pat, cols: []string{alias + "_path_edges"}})
+		}
+	}
+
+	var ctes []string
+	recursive := false
+
+	// nodeVarAlias tracks the first SQL alias each node pattern variable was
+	// bound to. A MATCH chain that repeats a variable (e.g.
+	// "(a)-->(b)-->(a)") must close the cycle back to that first occurrence
+	// rather than silently rebinding it to whichever alias saw it last, via
+	// addNodeSelect/bind, which would make the repeated name refer only to
+	// the final node and drop the constraint that the path actually closes.
+	nodeVarAlias := map[string]string{}
+
+	startAlias := "n0"
+	startCTE, startArgs := nodeFilterCTE(g.nodesTable, startAlias, q.Match.Start)
+	ctes = append(ctes, startCTE)
+	cteArgs = append(cteArgs, startArgs...)
+	addNodeSelect(startAlias, q.Match.Start.Variable, q.Match.Start)
+	if q.Match.Start.Variable != "" {
+		nodeVarAlias[q.Match.Start.Variable] = startAlias
+	}
+
+	fromSQL := startAlias
+	currentAlias := startAlias
+
+	for i, elem := range q.Match.Elements {
+		nodeAlias := fmt.Sprintf("n%d", i+1)
+
+		repeatCond := ""
+		if elem.Node.Variable != "" {
+			if firstAlias, seen := nodeVarAlias[elem.Node.Variable]; seen {
+				repeatCond = fmt.Sprintf(" AND %s.id = %s.id", nodeAlias, firstAlias)
+			} else {
+				nodeVarAlias[elem.Node.Variable] = nodeAlias
+			}
+		}
+
+		if elem.Rel.MinHops == 1 && elem.Rel.MaxHops == 1 {
+			edgeAlias := fmt.Sprintf("e%d", i+1)
+			joinSQL, joinArgs := fixedHopJoin(g.edgesTable, currentAlias, edgeAlias, elem.Rel)
+			fromSQL += " " + joinSQL
+			fromArgs = append(fromArgs, joinArgs...)
+
+			nodeCTE, nodeArgs := nodeFilterCTEStandalone(g.nodesTable, nodeAlias, elem.Node)
+			ctes = append(ctes, nodeCTE)
+			cteArgs = append(cteArgs, nodeArgs...)
+			fromSQL += fmt.Sprintf(" JOIN %s ON %s%s", nodeAlias, nodeJoinCondition(nodeAlias, edgeAlias, elem.Rel.Direction), repeatCond)
+
+			addRelSelect(edgeAlias, elem.Rel.Variable, elem.Rel)
+			addNodeSelect(nodeAlias, elem.Node.Variable, elem.Node)
+		} else {
+			recursive = true
+			recAlias := fmt.Sprintf("rec%d", i+1)
+			recCTE, recArgs := variableHopCTE(g.edgesTable, recAlias, elem.Rel)
+			ctes = append(ctes, recCTE)
+			cteArgs = append(cteArgs, recArgs...)
+
+			fromSQL += fmt.Sprintf(" JOIN %s ON %s.id = %s.start_id AND %s.depth BETWEEN %d AND %d",
+				recAlias, currentAlias, recAlias, recAlias, elem.Rel.MinHops, elem.Rel.MaxHops)
+
+			nodeCTE, nodeArgs := nodeFilterCTEStandalone(g.nodesTable, nodeAlias, elem.Node)
+			ctes = append(ctes, nodeCTE)
+			cteArgs = append(cteArgs, nodeArgs...)
+			fromSQL += fmt.Sprintf(" JOIN %s ON %s.id = %s.end_id%s", nodeAlias, nodeAlias, recAlias, repeatCond)
+
+			addPathSelect(recAlias, elem.Rel.Variable, elem.Rel)
+			addNodeSelect(nodeAlias, elem.Node.Variable, elem.Node)
+		}
+
+		currentAlias = nodeAlias
+	}
+
+	withKeyword := "WITH"
+	if recursive {
+		withKeyword = "WITH RECURSIVE"
+	}
+
+	fullSQL := fmt.Sprintf("%s %s\nSELECT %s\nFROM %s", withKeyword, strings.Join(ctes, ",\n"), strings.Join(selectExprs, ", "), fromSQL)
+
+	return &cypherPlan{
+		sql:      fullSQL,
+		args:     append(cteArgs, fromArgs...),
+		scanCols: scanCols,
+		vars:     vars,
+		order:    order,
+	}, nil
+}
+
+// nodeFilterCTE builds the starting CTE for the first node pattern in a
+// MATCH chain. Label filtering is pushed into SQL (via a cheap substring
+// test against the JSON-encoded labels column); property-map filtering is
+// intentionally left to Go-side evaluation, matching FindNodes/FindRelationships'
+// existing convention of decoding the JSON properties blob in application
+// code rather than assuming a JSON1-capable SQLite build.
+func nodeFilterCTE(nodesTable, alias string, pattern NodePattern) (string, []any) {
+	where, args := labelWhereClause("labels", pattern.Labels)
+	cte := fmt.Sprintf("%s AS (SELECT id, labels, properties FROM %s%s)", alias, nodesTable, where)
+	return cte, args
+}
+
+func nodeFilterCTEStandalone(nodesTable, alias string, pattern NodePattern) (string, []any) {
+	return nodeFilterCTE(nodesTable, alias, pattern)
+}
+
+func labelWhereClause(column string, labels []string) (string, []any) {
+	if len(labels) == 0 {
+		return "", nil
+	}
+	var conds []string
+	var args []any
+	for _, label := range labels {
+		conds = append(conds, fmt.Sprintf("instr(%s, ?) > 0", column))
+		args = append(args, `"`+label+`"`)
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// nodeJoinCondition returns the ON condition joining a fixed-hop
+// relationship's destination node alias against the edge alias, accounting
+// for direction. DirEither matches the node against whichever end of the
+// edge the traversal didn't already come from.
+func nodeJoinCondition(nodeAlias, edgeAlias string, dir Direction) string {
+	switch dir {
+	case DirLeft:
+		return fmt.Sprintf("%s.id = %s.source", nodeAlias, edgeAlias)
+	case DirRight:
+		return fmt.Sprintf("%s.id = %s.target", nodeAlias, edgeAlias)
+	default: // DirEither
+		return fmt.Sprintf("(%s.id = %s.target OR %s.id = %s.source)", nodeAlias, edgeAlias, nodeAlias, edgeAlias)
+	}
+}
+
+// fixedHopJoin builds the "JOIN edges eN ON ..." clause for a single,
+// fixed-length relationship hop from currentAlias to the edge alias.
+// DirEither is implemented as an OR across both orientations.
+func fixedHopJoin(edgesTable, currentAlias, edgeAlias string, rel RelPattern) (string, []any) {
+	var onCond string
+	switch rel.Direction {
+	case DirRight:
+		onCond = fmt.Sprintf("%s.source = %s.id", edgeAlias, currentAlias)
+	case DirLeft:
+		onCond = fmt.Sprintf("%s.target = %s.id", edgeAlias, currentAlias)
+	default: // DirEither
+		onCond = fmt.Sprintf("(%s.source = %s.id OR %s.target = %s.id)", edgeAlias, currentAlias, edgeAlias, currentAlias)
+	}
+
+	var args []any
+	if len(rel.Types) > 0 {
+		placeholders := make([]string, len(rel.Types))
+		for i, t := range rel.Types {
+			placeholders[i] = "?"
+			args = append(args, t)
+		}
+		onCond += fmt.Sprintf(" AND %s.edge_type IN (%s)", edgeAlias, strings.Join(placeholders, ", "))
+	}
+
+	return fmt.Sprintf("JOIN %s %s ON %s", edgesTable, edgeAlias, onCond), args
+}
+
+// variableHopCTE builds a "WITH RECURSIVE" member that performs a bounded
+// breadth-first walk over the edges table, tracking a comma-joined trail of
+// traversed edge ids (path_edges) so Result can later hydrate the full
+// []*Relationship path for a bound path variable. The walk is a trail (no
+// repeated edges) rather than a simple path (repeated nodes are allowed),
+// which is the usual openCypher variable-length semantics.
+func variableHopCTE(edgesTable, alias string, rel RelPattern) (string, []any) {
+	pairs := directionPairs(rel.Direction)
+
+	var baseSelects []string
+	var args []any
+	for _, pair := range pairs {
+		sel := fmt.Sprintf("SELECT e.%s AS start_id, e.%s AS end_id, 1 AS depth, CAST(e.id AS TEXT) AS path_edges FROM %s e",
+			pair[0], pair[1], edgesTable)
+		if len(rel.Types) > 0 {
+			where, typeArgs := edgeTypeWhere("e.edge_type", rel.Types)
+			sel += where
+			args = append(args, typeArgs...)
+		}
+		baseSelects = append(baseSelects, sel)
+	}
+
+	var recSelects []string
+	for _, pair := range pairs {
+		sel := fmt.Sprintf(`SELECT r.start_id, e.%s AS end_id, r.depth + 1, r.path_edges || ',' || e.id
+			FROM %s r JOIN %s e ON e.%s = r.end_id
+			WHERE r.depth < %d AND instr(',' || r.path_edges || ',', ',' || e.id || ',') = 0`,
+			pair[1], alias, edgesTable, pair[0], rel.MaxHops)
+		if len(rel.Types) > 0 {
+			where, typeArgs := edgeTypeWhere("e.edge_type", rel.Types)
+			sel += " AND " + strings.TrimPrefix(where, " WHERE ")
+			args = append(args, typeArgs...)
+		}
+		recSelects = append(recSelects, sel)
+	}
+
+	body := strings.Join(baseSelects, "\nUNION ALL\n") + "\nUNION ALL\n" + strings.Join(recSelects, "\nUNION ALL\n")
+	cte := fmt.Sprintf("%s(start_id, end_id, depth, path_edges) AS (\n%s\n)", alias, body)
+	return cte, args
+}
+
+func edgeTypeWhere(column string, types []string) (string, []any) {
+	placeholders := make([]string, len(types))
+	args := make([]any, len(types))
+	for i, t := range types {
+		placeholders[i] = "?"
+		args[i] = t
+	}
+	return fmt.Sprintf(" WHERE %s IN (%s)", column, strings.Join(placeholders, ", ")), args
+}
+
+// directionPairs returns the (fromColumn, toColumn) pairs a variable-length
+// walk should expand along. DirEither expands along both orientations,
+// which is how undirected relationship patterns are supported.
+func directionPairs(dir Direction) [][2]string {
+	switch dir {
+	case DirRight:
+		return [][2]string{{"source", "target"}}
+	case DirLeft:
+		return [][2]string{{"target", "source"}}
+	default:
+		return [][2]string{{"source", "target"}, {"target", "source"}}
+	}
+}
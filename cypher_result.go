@@ -0,0 +1,362 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Result is a streaming cursor over the rows produced by GraphDB.Query. Call
+// Next to advance and Record to read the current row; large result sets are
+// never materialized in memory all at once.
+type Result struct {
+	ctx    context.Context
+	g      *GraphDB
+	rows   *sql.Rows
+	plan   *cypherPlan
+	query  *Query
+	params map[string]any
+
+	cur map[string]any
+	err error
+}
+
+// Next advances to the next row that satisfies the query's WHERE clause and
+// pattern property filters, returning false once rows are exhausted or an
+// error occurs (check Err for the latter).
+func (r *Result) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	for r.rows.Next() {
+		row, err := r.scanRow()
+		if err != nil {
+			r.err = err
+			return false
+		}
+
+		matched, err := r.rowMatches(row)
+		if err != nil {
+			r.err = err
+			return false
+		}
+		if !matched {
+			continue
+		}
+
+		record, err := r.project(row)
+		if err != nil {
+			r.err = err
+			return false
+		}
+		r.cur = record
+		return true
+	}
+	r.err = r.rows.Err()
+	return false
+}
+
+// Record returns the current row as a map from RETURN alias (or the
+// expression's variable/property name if no alias was given) to its value:
+// a *Node, a *Relationship, a []*Relationship for a path variable, or a
+// scalar property value.
+func (r *Result) Record() map[string]any {
+	return r.cur
+}
+
+// Err returns the first error encountered while iterating, if any.
+func (r *Result) Err() error {
+	return r.err
+}
+
+// Close releases the underlying database rows.
+func (r *Result) Close() error {
+	return r.rows.Close()
+}
+
+// rawVar holds the raw scanned SQL values for one bound pattern variable,
+// before JSON decoding and WHERE evaluation.
+type rawVar struct {
+	kind varKind
+	vals map[string]any // col name -> scanned value
+}
+
+func (r *Result) scanRow() (map[string]rawVar, error) {
+	dest := make([]any, len(r.plan.scanCols))
+	ptrs := make([]any, len(r.plan.scanCols))
+	for i := range dest {
+		ptrs[i] = &dest[i]
+	}
+	if err := r.rows.Scan(ptrs...); err != nil {
+		return nil, fmt.Errorf("cypher: failed to scan row: %w", err)
+	}
+
+	byCol := make(map[string]any, len(r.plan.scanCols))
+	for i, col := range r.plan.scanCols {
+		byCol[col] = dest[i]
+	}
+
+	row := make(map[string]rawVar, len(r.plan.vars))
+	for name, binding := range r.plan.vars {
+		vals := make(map[string]any, len(binding.cols))
+		for _, col := range binding.cols {
+			vals[col] = byCol[col]
+		}
+		row[name] = rawVar{kind: binding.kind, vals: vals}
+	}
+	return row, nil
+}
+
+// rowMatches applies the MATCH pattern's property-map filters and the WHERE
+// clause, both evaluated in Go against the decoded node/relationship
+// properties (see the design note in cypher_plan.go).
+func (r *Result) rowMatches(row map[string]rawVar) (bool, error) {
+	nodes, rels, err := r.materialize(row)
+	if err != nil {
+		return false, err
+	}
+
+	for name, binding := range r.plan.vars {
+		switch binding.kind {
+		case varKindNode:
+			if !propertiesMatch(nodes[name].Properties, binding.pattern.Properties, r.params) {
+				return false, nil
+			}
+		case varKindRel:
+			if !propertiesMatch(rels[name].Properties, binding.relPat.Properties, r.params) {
+				return false, nil
+			}
+		}
+	}
+
+	if r.query.Where == nil {
+		return true, nil
+	}
+	val, err := evalExpr(r.query.Where, nodes, rels, r.params)
+	if err != nil {
+		return false, err
+	}
+	b, _ := val.(bool)
+	return b, nil
+}
+
+func (r *Result) materialize(row map[string]rawVar) (map[string]*Node, map[string]*Relationship, error) {
+	nodes := map[string]*Node{}
+	rels := map[string]*Relationship{}
+
+	for name, raw := range row {
+		switch raw.kind {
+		case varKindNode:
+			n, err := nodeFromRaw(raw.vals)
+			if err != nil {
+				return nil, nil, err
+			}
+			nodes[name] = n
+		case varKindRel:
+			rel, err := relFromRaw(raw.vals)
+			if err != nil {
+				return nil, nil, err
+			}
+			rels[name] = rel
+		case varKindPath:
+			// Path variables are hydrated on demand in project(), since
+			// they require extra queries against the edges table.
+		}
+	}
+
+	return nodes, rels, nil
+}
+
+func nodeFromRaw(vals map[string]any) (*Node, error) {
+	var id int64
+	for col, v := range vals {
+		if strings.HasSuffix(col, "_id") {
+			if iv, ok := v.(int64); ok {
+				id = iv
+			}
+		}
+	}
+
+	labels, properties := decodeLabelsAndProps(vals)
+	return &Node{ID: id, Labels: labels, Properties: properties}, nil
+}
+
+func relFromRaw(vals map[string]any) (*Relationship, error) {
+	var id, source, target int64
+	var edgeType string
+	var propsText string
+	for col, v := range vals {
+		switch {
+		case strings.HasSuffix(col, "_id"):
+			if iv, ok := v.(int64); ok {
+				id = iv
+			}
+		case strings.HasSuffix(col, "_source"):
+			if iv, ok := v.(int64); ok {
+				source = iv
+			}
+		case strings.HasSuffix(col, "_target"):
+			if iv, ok := v.(int64); ok {
+				target = iv
+			}
+		case strings.HasSuffix(col, "_edge_type"):
+			edgeType = toString(v)
+		case strings.HasSuffix(col, "_properties"):
+			propsText = toString(v)
+		}
+	}
+
+	props := map[string]any{}
+	if propsText != "" {
+		if err := json.Unmarshal([]byte(propsText), &props); err != nil {
+			return nil, fmt.Errorf("cypher: failed to decode relationship properties: %w", err)
+		}
+	}
+
+	return &Relationship{ID: id, StartNode: source, EndNode: target, Type: edgeType, Properties: props}, nil
+}
+
+func decodeLabelsAndProps(vals map[string]any) ([]string, map[string]any) {
+	var labelsText, propsText string
+	for col, v := range vals {
+		switch {
+		case strings.HasSuffix(col, "_labels"):
+			labelsText = toString(v)
+		case strings.HasSuffix(col, "_properties"):
+			propsText = toString(v)
+		}
+	}
+
+	var labels []string
+	if labelsText != "" && labelsText != "[]" {
+		_ = json.Unmarshal([]byte(labelsText), &labels)
+	}
+
+	props := map[string]any{}
+	if propsText != "" {
+		_ = json.Unmarshal([]byte(propsText), &props)
+	}
+
+	return labels, props
+}
+
+func toString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	default:
+		return ""
+	}
+}
+
+// project builds the final RETURN row for the current query, hydrating any
+// path variables from the edges table as needed.
+func (r *Result) project(row map[string]rawVar) (map[string]any, error) {
+	nodes, rels, err := r.materialize(row)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]any{}
+	for _, item := range r.query.Return {
+		key := item.Alias
+		if key == "" {
+			key = returnItemName(item.Expr)
+		}
+
+		switch e := item.Expr.(type) {
+		case VarRef:
+			if raw, ok := row[e.Name]; ok && raw.kind == varKindPath {
+				path, err := r.hydratePath(raw)
+				if err != nil {
+					return nil, err
+				}
+				out[key] = path
+				continue
+			}
+			if n, ok := nodes[e.Name]; ok {
+				out[key] = n
+				continue
+			}
+			if rel, ok := rels[e.Name]; ok {
+				out[key] = rel
+				continue
+			}
+			return nil, fmt.Errorf("cypher: unbound variable %q in RETURN", e.Name)
+		case PropertyAccess:
+			val, err := evalExpr(e, nodes, rels, r.params)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = val
+		default:
+			val, err := evalExpr(e, nodes, rels, r.params)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = val
+		}
+	}
+	return out, nil
+}
+
+func returnItemName(e Expr) string {
+	switch v := e.(type) {
+	case VarRef:
+		return v.Name
+	case PropertyAccess:
+		return v.Variable + "." + v.Property
+	default:
+		return ""
+	}
+}
+
+// hydratePath resolves a variable-length relationship variable's
+// comma-joined edge id trail into the full ordered []*Relationship.
+func (r *Result) hydratePath(raw rawVar) ([]*Relationship, error) {
+	var trail string
+	for _, v := range raw.vals {
+		trail = toString(v)
+	}
+	trail = strings.Trim(trail, ",")
+	if trail == "" {
+		return nil, nil
+	}
+
+	ids := strings.Split(trail, ",")
+	rels := make([]*Relationship, 0, len(ids))
+	for _, idStr := range ids {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cypher: invalid edge id %q in path trail: %w", idStr, err)
+		}
+		rel, err := r.g.relationshipByID(r.ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, rel)
+	}
+	return rels, nil
+}
+
+// propertiesMatch reports whether actual contains every key/value pair in
+// pattern (after resolving parameters), the same equality semantics
+// FindNodes/FindRelationships already apply.
+func propertiesMatch(actual map[string]any, pattern map[string]Expr, params map[string]any) bool {
+	for key, expr := range pattern {
+		want, err := evalExpr(expr, nil, nil, params)
+		if err != nil {
+			return false
+		}
+		got, ok := actual[key]
+		if !ok || !valuesEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+)
+
+// newTestGraphDB opens a GraphDB against a fresh in-memory database. The
+// "graph" virtual table itself requires the real graph extension, but
+// GraphDB.Query compiles straight to SQL against the plain nodesTable/
+// edgesTable backing tables, so tests insert into those directly rather
+// than going through CreateNode/CreateRelationship.
+func newTestGraphDB(t *testing.T) *GraphDB {
+	t.Helper()
+	db := openTestDB(t)
+	g, err := NewGraphDB(context.Background(), db, "graph")
+	if err != nil {
+		t.Fatalf("failed to create graph db: %v", err)
+	}
+	return g
+}
+
+func insertTestNode(t *testing.T, g *GraphDB, id int64, labels, properties string) {
+	t.Helper()
+	if _, err := g.db.Exec("INSERT INTO "+g.nodesTable+"(id, labels, properties) VALUES (?, ?, ?)", id, labels, properties); err != nil {
+		t.Fatalf("failed to insert node %d: %v", id, err)
+	}
+}
+
+func insertTestEdge(t *testing.T, g *GraphDB, id, source, target int64, edgeType string) {
+	t.Helper()
+	if _, err := g.db.Exec("INSERT INTO "+g.edgesTable+"(id, source, target, edge_type, weight, properties) VALUES (?, ?, ?, ?, ?, ?)",
+		id, source, target, edgeType, 1.0, "{}"); err != nil {
+		t.Fatalf("failed to insert edge %d: %v", id, err)
+	}
+}
+
+// TestQueryMatchWhereReturn covers a basic single-hop MATCH with a WHERE
+// predicate and a property RETURN.
+func TestQueryMatchWhereReturn(t *testing.T) {
+	g := newTestGraphDB(t)
+	insertTestNode(t, g, 1, `["Person"]`, `{"name": "alice"}`)
+	insertTestNode(t, g, 2, `["Person"]`, `{"name": "bob"}`)
+	insertTestEdge(t, g, 1, 1, 2, "KNOWS")
+
+	result, err := g.Query(context.Background(), `MATCH (a:Person)-[:KNOWS]->(b:Person) WHERE a.name = 'alice' RETURN a.name AS an, b.name AS bn`, nil)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer result.Close()
+
+	if !result.Next() {
+		t.Fatalf("expected a row, got none (err: %v)", result.Err())
+	}
+	rec := result.Record()
+	if rec["an"] != "alice" || rec["bn"] != "bob" {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+	if result.Next() {
+		t.Fatalf("expected exactly one row, got another: %+v", result.Record())
+	}
+}
+
+// TestQueryVariableLengthHops covers a "*min..max" relationship pattern
+// reaching a node two hops away.
+func TestQueryVariableLengthHops(t *testing.T) {
+	g := newTestGraphDB(t)
+	insertTestNode(t, g, 1, `["Person"]`, `{"name": "alice"}`)
+	insertTestNode(t, g, 2, `["Person"]`, `{"name": "bob"}`)
+	insertTestNode(t, g, 3, `["Person"]`, `{"name": "carol"}`)
+	insertTestEdge(t, g, 1, 1, 2, "KNOWS")
+	insertTestEdge(t, g, 2, 2, 3, "KNOWS")
+
+	result, err := g.Query(context.Background(), `MATCH (a:Person)-[:KNOWS*1..2]->(b:Person) WHERE a.name = 'alice' RETURN b.name AS bn`, nil)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer result.Close()
+
+	seen := map[string]bool{}
+	for result.Next() {
+		seen[result.Record()["bn"].(string)] = true
+	}
+	if err := result.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	if !seen["bob"] || !seen["carol"] {
+		t.Fatalf("expected both bob (1 hop) and carol (2 hops) reachable, got %v", seen)
+	}
+}
+
+// TestQueryRepeatedVariableRequiresClosedCycle verifies that repeating a
+// pattern variable (e.g. "(a)-->(b)-->(a)") only matches when the path
+// actually closes back to the first node, rather than silently matching
+// any node reachable at that position (planQuery's prior bug: the second
+// "(a)" bound to whichever alias it last saw with no SQL tying it to the
+// first "(a)").
+func TestQueryRepeatedVariableRequiresClosedCycle(t *testing.T) {
+	g := newTestGraphDB(t)
+	insertTestNode(t, g, 1, `[]`, `{}`)
+	insertTestNode(t, g, 2, `[]`, `{}`)
+	insertTestNode(t, g, 3, `[]`, `{}`)
+	insertTestEdge(t, g, 1, 1, 2, "KNOWS")
+	insertTestEdge(t, g, 2, 2, 3, "KNOWS")
+
+	result, err := g.Query(context.Background(), `MATCH (a)-[:KNOWS]->(b)-[:KNOWS]->(a) RETURN a`, nil)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer result.Close()
+
+	if result.Next() {
+		t.Fatalf("expected no rows for a chain that never closes back to a, got %+v", result.Record())
+	}
+	if err := result.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+
+	// Close the triangle: now node 1 -> 2 -> 1 is a real cycle.
+	insertTestEdge(t, g, 3, 2, 1, "KNOWS")
+
+	result, err = g.Query(context.Background(), `MATCH (a)-[:KNOWS]->(b)-[:KNOWS]->(a) RETURN a, b`, nil)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer result.Close()
+
+	if !result.Next() {
+		t.Fatalf("expected the closed cycle to match, got none (err: %v)", result.Err())
+	}
+	rec := result.Record()
+	a, ok := rec["a"].(*Node)
+	if !ok {
+		t.Fatalf("expected a to be a *Node, got %T", rec["a"])
+	}
+	b, ok := rec["b"].(*Node)
+	if !ok {
+		t.Fatalf("expected b to be a *Node, got %T", rec["b"])
+	}
+	if a.ID != 1 || b.ID != 2 {
+		t.Fatalf("expected a=1, b=2, got a=%d, b=%d", a.ID, b.ID)
+	}
+}
@@ -1,5 +1,5 @@
-//go:build !windows && cgo
-// +build !windows,cgo
+//go:build cgo
+// +build cgo
 
 package sqlite
 
@@ -11,141 +11,140 @@ import "C"
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"log/slog"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime"
-	"sync"
 
 	"github.com/mattn/go-sqlite3"
 )
 
 type DB = sql.DB
 
-// LoadGraphExtension loads the embedded graph extension into the database
+// LoadGraphExtension loads the embedded graph extension into db using the
+// default ExtensionLoader.
 func LoadGraphExtension(db *sql.DB) error {
-	return loadExtension(db, GraphExtension, "graph_extension.so", "sqlite3_graph_init")
+	return loadExtension(db, defaultExtensionLoader(), GraphExtension, "graph_extension", "sqlite3_graph_init")
 }
 
-// LoadVecExtension loads the embedded vector extension into the database
+// LoadVecExtension loads the embedded vector extension into db using the
+// default ExtensionLoader.
 func LoadVecExtension(db *sql.DB) error {
-	return loadExtension(db, VecExtension, "vec_extension.so", "sqlite3_vec_init")
+	return loadExtension(db, defaultExtensionLoader(), VecExtension, "vec_extension", "sqlite3_vec_init")
 }
 
-// loadExtension is a helper function that writes the embedded extension to a temporary file
-// and loads it into the database
-func loadExtension(db *sql.DB, extensionData []byte, filename, entryPoint string) error {
-	// Create a temporary directory with a unique name
-	tmpDir, err := os.MkdirTemp("", "sqlite-ext-")
+// loadExtension prepares extensionData via loader and, if it produced
+// something to load, loads it into db through SQLite's load_extension() SQL
+// function.
+func loadExtension(db *sql.DB, loader ExtensionLoader, extensionData []byte, name, entryPoint string) error {
+	path, ok, err := loader.Prepare(extensionData, name)
 	if err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+		return fmt.Errorf("failed to prepare extension %s: %w", name, err)
 	}
-	defer os.RemoveAll(tmpDir)
-
-	tmpFile := filepath.Join(tmpDir, filename)
-
-	// Write the extension data to the temporary file with executable permissions
-	if err := os.WriteFile(tmpFile, extensionData, 0755); err != nil {
-		return fmt.Errorf("failed to write extension to temporary file: %w", err)
+	if !ok {
+		return nil
 	}
 
-	// On macOS, we might need to remove quarantine attributes
-	if runtime.GOOS == "darwin" {
-		// Try to remove quarantine attribute (this might fail but that's OK)
-		exec.Command("xattr", "-d", "com.apple.quarantine", tmpFile).Run()
+	query := fmt.Sprintf("SELECT load_extension('%s', '%s')", path, entryPoint)
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to load extension %s: %w", name, err)
 	}
 
-	// Load the extension into the database
-	query := fmt.Sprintf("SELECT load_extension('%s', '%s')", tmpFile, entryPoint)
-	_, err = db.Exec(query)
-	if err != nil {
-		return fmt.Errorf("failed to load extension %s: %w", filename, err)
+	return nil
+}
+
+// newConnectHook returns a sqlite3.SQLiteDriver.ConnectHook that loads the
+// graph/vec extensions via loader on every new pooled connection.
+func newConnectHook(loader ExtensionLoader) func(*sqlite3.SQLiteConn) error {
+	return func(conn *sqlite3.SQLiteConn) error {
+		// Enable extension loading first
+		if _, err := conn.Exec("PRAGMA load_extension = 1", nil); err != nil {
+			wrappedErr := fmt.Errorf("store.NewDB: failed to enable extension loading: %w", err)
+			slog.Error(wrappedErr.Error())
+			return wrappedErr
+		}
+
+		if err := connectLoadExtension(conn, loader, GraphExtension, "graph_extension", "sqlite3_graph_init"); err != nil {
+			wrappedErr := fmt.Errorf("store.NewDB: failed to load graph extension: %w", err)
+			slog.Error(wrappedErr.Error())
+			return wrappedErr
+		}
+
+		if err := connectLoadExtension(conn, loader, VecExtension, "vec_extension", "sqlite3_vec_init"); err != nil {
+			wrappedErr := fmt.Errorf("store.NewDB: failed to load vec extension: %w", err)
+			slog.Error(wrappedErr.Error())
+			return wrappedErr
+		}
+
+		return nil
 	}
+}
 
-	return nil
+// extensionConnector is a driver.Connector wrapping a *sqlite3.SQLiteDriver
+// built fresh for one NewDB call. Going through sql.OpenDB with a connector
+// (rather than sql.Register + sql.Open) means each call's driver instance -
+// and the ExtensionLoader its ConnectHook closes over - is only ever
+// referenced by the *sql.DB it backs, so it's garbage collected once that
+// DB is closed instead of accumulating forever in database/sql's global,
+// un-unregisterable driver registry.
+type extensionConnector struct {
+	driver *sqlite3.SQLiteDriver
+	dsn    string
 }
 
-var registerOnce sync.Once
-
-func NewDB(ctx context.Context, dsn string) (db *sql.DB, err error) {
-	// Register the custom SQLite driver only once
-	registerOnce.Do(func() {
-		sql.Register("sqlite3_with_extensions", &sqlite3.SQLiteDriver{
-			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
-				// Enable extension loading first
-				if _, err := conn.Exec("PRAGMA load_extension = 1", nil); err != nil {
-					wrappedErr := fmt.Errorf("store.NewDB: failed to enable extension loading: %w", err)
-					slog.Error(wrappedErr.Error())
-					return wrappedErr
-				}
-
-				// Create temporary files for the extensions
-				graphTmpFile, err := writeExtensionToTemp(GraphExtension, "graph_extension.so")
-				if err != nil {
-					wrappedErr := fmt.Errorf("store.NewDB: failed to write graph extension: %w", err)
-					slog.Error(wrappedErr.Error())
-					return wrappedErr
-				}
-				defer os.Remove(graphTmpFile)
-
-				vecTmpFile, err := writeExtensionToTemp(VecExtension, "vec_extension.so")
-				if err != nil {
-					wrappedErr := fmt.Errorf("store.NewDB: failed to write vec extension: %w", err)
-					slog.Error(wrappedErr.Error())
-					return wrappedErr
-				}
-				defer os.Remove(vecTmpFile)
-
-				// Load the extensions
-				if err := conn.LoadExtension(graphTmpFile, "sqlite3_graph_init"); err != nil {
-					wrappedErr := fmt.Errorf("store.NewDB: failed to load graph extension: %w", err)
-					slog.Error(wrappedErr.Error())
-					return wrappedErr
-				}
-
-				if err := conn.LoadExtension(vecTmpFile, "sqlite3_vec_init"); err != nil {
-					wrappedErr := fmt.Errorf("store.NewDB: failed to load vec extension: %w", err)
-					slog.Error(wrappedErr.Error())
-					return wrappedErr
-				}
-
-				return nil
-			},
-		})
-	})
-
-	// Open the database with the custom driver
-	db, err = sql.Open("sqlite3_with_extensions", dsn)
-	if err != nil {
-		wrappedErr := fmt.Errorf("store.NewDB: failed to open database: %w", err)
-		slog.Error(wrappedErr.Error())
-		return nil, wrappedErr
+func (c *extensionConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *extensionConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func NewDB(ctx context.Context, dsn string, opts ...DBOption) (*sql.DB, error) {
+	var o dbOptions
+	for _, opt := range opts {
+		opt(&o)
 	}
+	if o.extensionLoader == nil {
+		o.extensionLoader = defaultExtensionLoader()
+	}
+
+	connector := &extensionConnector{
+		driver: &sqlite3.SQLiteDriver{ConnectHook: newConnectHook(o.extensionLoader)},
+		dsn:    dsn,
+	}
+
+	// Open the database through the per-call connector. sql.OpenDB doesn't
+	// dial or validate anything itself - PingContext below is what
+	// surfaces a bad DSN or a failed ConnectHook.
+	db := sql.OpenDB(connector)
 	if err := db.PingContext(ctx); err != nil {
 		wrappedErr := fmt.Errorf("store.NewDB: failed to ping database: %w", err)
 		slog.Error(wrappedErr.Error())
 		return nil, wrappedErr
 	}
+
+	if o.runMigrations {
+		migrator := NewMigrator(db, o.extraMigrations...)
+		if err := migrator.Up(ctx); err != nil {
+			wrappedErr := fmt.Errorf("store.NewDB: failed to run migrations: %w", err)
+			slog.Error(wrappedErr.Error())
+			return nil, wrappedErr
+		}
+	}
+
 	return db, nil
 }
 
-// writeExtensionToTemp writes extension data to a temporary file and returns the path
-func writeExtensionToTemp(extensionData []byte, filename string) (string, error) {
-	tmpFile, err := os.CreateTemp("", filename)
+// connectLoadExtension prepares extensionData via loader and, if it produced
+// something to load, loads it into the freshly-opened conn through the
+// driver-level LoadExtension call used by sqlite3.SQLiteDriver's ConnectHook.
+func connectLoadExtension(conn *sqlite3.SQLiteConn, loader ExtensionLoader, extensionData []byte, name, entryPoint string) error {
+	path, ok, err := loader.Prepare(extensionData, name)
 	if err != nil {
-		return "", err
-	}
-	defer tmpFile.Close()
-
-	if err := tmpFile.Chmod(0755); err != nil {
-		return "", err
+		return fmt.Errorf("failed to prepare extension %s: %w", name, err)
 	}
-
-	if _, err := tmpFile.Write(extensionData); err != nil {
-		return "", err
+	if !ok {
+		return nil
 	}
-
-	return tmpFile.Name(), nil
+	return conn.LoadExtension(path, entryPoint)
 }
@@ -0,0 +1,229 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Embedder turns text into vectors, so DocumentStore isn't tied to any one
+// embedding provider (OpenAI, a local model, etc). EmbedDocuments is kept
+// separate from EmbedQuery because many providers use a different model or
+// prompt prefix for indexing than for querying.
+type Embedder interface {
+	EmbedQuery(ctx context.Context, text string) ([]float32, error)
+	EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Document is a unit of content added to a DocumentStore via AddDocuments.
+type Document struct {
+	Content   string
+	Metadata  map[string]any
+	ContextID string
+}
+
+// ScoredDocument is a Document returned from SimilaritySearch, carrying the
+// score that produced its rank.
+type ScoredDocument struct {
+	ID        int64
+	Content   string
+	Metadata  map[string]any
+	ContextID string
+	CreatedAt time.Time
+	Score     float64
+}
+
+// DocumentStore pairs a VectorDB with a companion table holding each
+// embedding's source content and metadata, so SimilaritySearch can return
+// full documents instead of bare (id, distance) pairs.
+type DocumentStore struct {
+	db        *sql.DB
+	vectors   *VectorDB
+	embedder  Embedder
+	docsTable string
+}
+
+// docsTableDDL is the companion table holding document content and
+// metadata, keyed by the same rowid as the vec0 embedding table.
+func docsTableDDL(docsTable string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id INTEGER PRIMARY KEY,
+		content TEXT NOT NULL,
+		metadata JSON,
+		context_id TEXT,
+		created_at TIMESTAMP NOT NULL
+	)`, docsTable)
+}
+
+// NewDocumentStore creates a DocumentStore backed by a vec0 embedding table
+// and a companion "<name>_docs" table, using embedder to turn document and
+// query text into vectors for AddDocuments and SimilaritySearch.
+func NewDocumentStore(ctx context.Context, db *sql.DB, name string, dimensions int, embedder Embedder, opts ...VectorDBOption) (*DocumentStore, error) {
+	vectors, err := NewVectorDB(ctx, db, name, dimensions, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &DocumentStore{
+		db:        db,
+		vectors:   vectors,
+		embedder:  embedder,
+		docsTable: vectors.tableName + "_docs",
+	}
+
+	if _, err := db.ExecContext(ctx, docsTableDDL(ds.docsTable)); err != nil {
+		return nil, fmt.Errorf("failed to create docs table: %w", err)
+	}
+
+	return ds, nil
+}
+
+// AddDocuments embeds docs and inserts each one's content/metadata row and
+// embedding in a single transaction, returning the assigned rowids in the
+// same order as docs.
+func (ds *DocumentStore) AddDocuments(ctx context.Context, docs []Document) ([]int64, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, d := range docs {
+		texts[i] = d.Content
+	}
+
+	vectors, err := ds.embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed documents: %w", err)
+	}
+	if len(vectors) != len(docs) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d documents", len(vectors), len(docs))
+	}
+
+	if err := ds.vectors.ensureFTSTable(ctx); err != nil {
+		return nil, err
+	}
+
+	tx, err := ds.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin add-documents transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertDoc := fmt.Sprintf("INSERT INTO %s(content, metadata, context_id, created_at) VALUES (?, ?, ?, ?)", ds.docsTable)
+	insertVec := fmt.Sprintf("INSERT INTO %s(rowid, %s) VALUES (?, ?)", ds.vectors.tableName, defaultVectorColumn)
+	now := time.Now().UTC()
+
+	ids := make([]int64, len(docs))
+	for i, d := range docs {
+		metadataJSON, err := json.Marshal(d.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx, insertDoc, d.Content, string(metadataJSON), d.ContextID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert document: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read inserted document id: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, insertVec, id, float32sToBytes(vectors[i])); err != nil {
+			return nil, fmt.Errorf("failed to insert embedding for document %d: %w", id, err)
+		}
+
+		if err := indexTextTx(ctx, tx, ds.vectors.ftsTable(), uint64(id), d.Content, tagsString(d.Metadata), d.ContextID); err != nil {
+			return nil, err
+		}
+
+		ids[i] = id
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit add-documents transaction: %w", err)
+	}
+
+	return ids, nil
+}
+
+// SimilaritySearch embeds query, runs a KNN match against the embedding
+// table, and joins back to the docs table to return full documents ranked
+// by similarity. k sets opts.TopK when positive.
+func (ds *DocumentStore) SimilaritySearch(ctx context.Context, query string, k int, opts SearchOptions) ([]ScoredDocument, error) {
+	if k > 0 {
+		opts.TopK = k
+	}
+
+	queryVector, err := ds.embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	hits, err := ds.vectors.SearchSimilarVectors(ctx, queryVector, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	return ds.loadDocsByHits(ctx, hits)
+}
+
+// loadDocsByHits joins hits back to ds.docsTable, returning one ScoredDocument
+// per hit in hits' own order (its rank by similarity) carrying that hit's
+// Score. A hit whose document row is missing (e.g. deleted out from under a
+// stale embedding) is silently dropped rather than erroring.
+func (ds *DocumentStore) loadDocsByHits(ctx context.Context, hits []VectorResult) ([]ScoredDocument, error) {
+	placeholders := make([]string, len(hits))
+	args := make([]any, len(hits))
+	scoreByID := make(map[int64]float64, len(hits))
+	for i, h := range hits {
+		placeholders[i] = "?"
+		args[i] = h.ID
+		scoreByID[h.ID] = h.Score
+	}
+
+	docsQuery := fmt.Sprintf(
+		"SELECT id, content, metadata, context_id, created_at FROM %s WHERE id IN (%s)",
+		ds.docsTable, strings.Join(placeholders, ", "))
+
+	rows, err := ds.db.QueryContext(ctx, docsQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load documents: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]ScoredDocument, len(hits))
+	for rows.Next() {
+		var doc ScoredDocument
+		var metadataJSON, contextID sql.NullString
+		if err := rows.Scan(&doc.ID, &doc.Content, &metadataJSON, &contextID, &doc.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan document row: %w", err)
+		}
+		if metadataJSON.Valid && metadataJSON.String != "" {
+			if err := json.Unmarshal([]byte(metadataJSON.String), &doc.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to decode metadata for document %d: %w", doc.ID, err)
+			}
+		}
+		doc.ContextID = contextID.String
+		doc.Score = scoreByID[doc.ID]
+		byID[doc.ID] = doc
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]ScoredDocument, 0, len(hits))
+	for _, h := range hits {
+		if doc, ok := byID[h.ID]; ok {
+			results = append(results, doc)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// newTestDocumentStore creates a DocumentStore's docsTable directly against
+// db, bypassing NewDocumentStore (which requires the real vec0 extension to
+// create its companion embedding table). loadDocsByHits only ever touches
+// docsTable, so this is sufficient to exercise it.
+func newTestDocumentStore(t *testing.T, db *sql.DB) *DocumentStore {
+	t.Helper()
+	ds := &DocumentStore{db: db, docsTable: "docs"}
+	if _, err := db.Exec(docsTableDDL(ds.docsTable)); err != nil {
+		t.Fatalf("failed to create docs table: %v", err)
+	}
+	return ds
+}
+
+func insertTestDoc(t *testing.T, db *sql.DB, id int64, content string) {
+	t.Helper()
+	_, err := db.Exec("INSERT INTO docs(id, content, metadata, context_id, created_at) VALUES (?, ?, ?, ?, ?)",
+		id, content, `{"source":"test"}`, "ctx-1", time.Now().UTC())
+	if err != nil {
+		t.Fatalf("failed to insert test doc %d: %v", id, err)
+	}
+}
+
+// TestLoadDocsByHitsJoinsAndPreservesRankOrder verifies SimilaritySearch's
+// join-back-to-docs step returns documents in the hits' similarity-rank
+// order (not docsTable's row order) and carries each hit's own Score.
+func TestLoadDocsByHitsJoinsAndPreservesRankOrder(t *testing.T) {
+	db := openTestDB(t)
+	ds := newTestDocumentStore(t, db)
+
+	insertTestDoc(t, db, 1, "alpha")
+	insertTestDoc(t, db, 2, "beta")
+	insertTestDoc(t, db, 3, "gamma")
+
+	hits := []VectorResult{
+		{ID: 3, Score: 0.9},
+		{ID: 1, Score: 0.7},
+	}
+
+	got, err := ds.loadDocsByHits(context.Background(), hits)
+	if err != nil {
+		t.Fatalf("loadDocsByHits failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(got))
+	}
+	if got[0].ID != 3 || got[0].Content != "gamma" || got[0].Score != 0.9 {
+		t.Fatalf("expected gamma (id 3, score 0.9) first, got %+v", got[0])
+	}
+	if got[1].ID != 1 || got[1].Content != "alpha" || got[1].Score != 0.7 {
+		t.Fatalf("expected alpha (id 1, score 0.7) second, got %+v", got[1])
+	}
+	if got[0].Metadata["source"] != "test" {
+		t.Fatalf("expected metadata to decode, got %+v", got[0].Metadata)
+	}
+	if got[0].ContextID != "ctx-1" {
+		t.Fatalf("expected context id to carry through, got %q", got[0].ContextID)
+	}
+}
+
+// TestLoadDocsByHitsDropsMissingDoc verifies a hit whose document row no
+// longer exists is silently skipped instead of erroring or leaving a zero
+// value in the results.
+func TestLoadDocsByHitsDropsMissingDoc(t *testing.T) {
+	db := openTestDB(t)
+	ds := newTestDocumentStore(t, db)
+
+	insertTestDoc(t, db, 1, "alpha")
+
+	hits := []VectorResult{
+		{ID: 1, Score: 0.5},
+		{ID: 99, Score: 0.9},
+	}
+
+	got, err := ds.loadDocsByHits(context.Background(), hits)
+	if err != nil {
+		t.Fatalf("loadDocsByHits failed: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("expected only the existing document, got %+v", got)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows && !generate
+// +build !windows,!generate
+
+package sqlite
+
+import (
+	_ "embed"
+)
+
+// GraphExtension is the graph extension shared library for this platform.
+//
+//go:embed graph_extension.so
+var GraphExtension []byte
+
+// VecExtension is the vector extension shared library for this platform.
+//
+//go:embed vec_extension.so
+var VecExtension []byte
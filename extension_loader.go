@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ExtensionLoader prepares embedded extension bytes for loading by
+// mattn/go-sqlite3, which only accepts a filesystem path (or, on Linux, a
+// /proc/self/fd path). Implementations choose how - or whether - that path
+// gets materialized: TempFileLoader writes a real file, MemfdLoader (Linux)
+// never touches the filesystem, and the static-link build's loader has
+// nothing to load at all.
+type ExtensionLoader interface {
+	// Prepare returns a path conn.LoadExtension can load data from under
+	// name. ok is false when there is nothing to load because the
+	// extension was already registered some other way (e.g. statically
+	// linked via sqlite3_auto_extension); callers should skip the
+	// LoadExtension call entirely in that case.
+	Prepare(data []byte, name string) (path string, ok bool, err error)
+}
+
+// extensionSuffix is the shared-library filename suffix for the current
+// platform, matching GraphExtension/VecExtension's embed tags.
+func extensionSuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".dll"
+	}
+	return ".so"
+}
+
+// TempFileLoader materializes extension bytes under a content-addressed
+// filename in Dir (or a default temp directory), so repeated loads of the
+// same extension reuse the same file instead of writing a fresh temp file
+// per connection.
+type TempFileLoader struct {
+	// Dir is the directory extension files are written to. Empty means
+	// resolve one lazily from XDG_RUNTIME_DIR or os.TempDir().
+	Dir string
+}
+
+// extensionDir resolves the directory TempFileLoader writes into.
+func (l *TempFileLoader) extensionDir() string {
+	if l.Dir != "" {
+		return l.Dir
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
+// Prepare writes data to a content-addressed path under l.extensionDir(),
+// skipping the write if a file with that hash already exists.
+func (l *TempFileLoader) Prepare(data []byte, name string) (string, bool, error) {
+	sum := sha256.Sum256(data)
+	filename := hex.EncodeToString(sum[:16]) + extensionSuffix()
+	path := filepath.Join(l.extensionDir(), filename)
+
+	if _, err := os.Stat(path); err == nil {
+		return path, true, nil
+	}
+
+	tmp, err := os.CreateTemp(l.extensionDir(), name+".tmp-*")
+	if err != nil {
+		return "", false, fmt.Errorf("extension loader: failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", false, fmt.Errorf("extension loader: failed to write %s: %w", name, err)
+	}
+	if err := tmp.Chmod(0o755); err != nil {
+		tmp.Close()
+		return "", false, fmt.Errorf("extension loader: failed to chmod %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", false, fmt.Errorf("extension loader: failed to close %s: %w", name, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		// Another loader may have raced us to the same content-addressed
+		// path; if it's there now, that's fine.
+		if _, statErr := os.Stat(path); statErr == nil {
+			return path, true, nil
+		}
+		return "", false, fmt.Errorf("extension loader: failed to install %s: %w", name, err)
+	}
+
+	return path, true, nil
+}
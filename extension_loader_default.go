@@ -0,0 +1,10 @@
+//go:build !sqlite_static_ext
+// +build !sqlite_static_ext
+
+package sqlite
+
+// defaultExtensionLoader is used by NewDB when no WithExtensionLoader option
+// is given.
+func defaultExtensionLoader() ExtensionLoader {
+	return &TempFileLoader{}
+}
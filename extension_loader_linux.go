@@ -0,0 +1,60 @@
+//go:build linux
+// +build linux
+
+package sqlite
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// MemfdLoader loads extensions from an anonymous, unlinked memfd instead of
+// writing them to disk, so the extension bytes are never visible in any
+// directory listing. Linux-only: memfd_create has no portable equivalent.
+type MemfdLoader struct{}
+
+// memfdCache deduplicates memfd_create calls by content hash, the memfd
+// counterpart to TempFileLoader's content-addressed filename: db.go's
+// ConnectHook runs Prepare once per new pooled connection, not once per
+// process, so without this every connection would leak its own memfd.
+var (
+	memfdCacheMu sync.Mutex
+	memfdCache   = map[string]string{}
+)
+
+// Prepare creates a sealed memfd containing data and returns its
+// /proc/self/fd path, which mattn/go-sqlite3's LoadExtension can open like
+// any other file path. Repeated calls with the same data reuse the same
+// memfd rather than creating (and leaking) a new one each time.
+func (MemfdLoader) Prepare(data []byte, name string) (string, bool, error) {
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:])
+
+	memfdCacheMu.Lock()
+	defer memfdCacheMu.Unlock()
+
+	if path, ok := memfdCache[key]; ok {
+		return path, true, nil
+	}
+
+	fd, err := unix.MemfdCreate(name, 0)
+	if err != nil {
+		return "", false, fmt.Errorf("extension loader: memfd_create failed for %s: %w", name, err)
+	}
+
+	if _, err := unix.Write(fd, data); err != nil {
+		unix.Close(fd)
+		return "", false, fmt.Errorf("extension loader: failed to write %s to memfd: %w", name, err)
+	}
+
+	// The fd is intentionally left open for the lifetime of the process:
+	// sqlite3_load_extension only reads it at open time via the
+	// /proc/self/fd path, and closing it immediately would race that read.
+	path := fmt.Sprintf("/proc/self/fd/%d", fd)
+	memfdCache[key] = path
+	return path, true, nil
+}
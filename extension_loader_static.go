@@ -0,0 +1,50 @@
+//go:build sqlite_static_ext && cgo
+// +build sqlite_static_ext,cgo
+
+package sqlite
+
+/*
+#cgo CFLAGS: -DSQLITE_ENABLE_LOAD_EXTENSION=1
+#cgo LDFLAGS: -L${SRCDIR} -lgraph_extension_static -lvec_extension_static
+#include <sqlite3.h>
+
+extern int sqlite3_graph_init(sqlite3 *db, char **pzErrMsg, const sqlite3_api_routines *pApi);
+extern int sqlite3_vec_init(sqlite3 *db, char **pzErrMsg, const sqlite3_api_routines *pApi);
+
+static void register_static_extensions(void) {
+	sqlite3_auto_extension((void (*)(void)) sqlite3_graph_init);
+	sqlite3_auto_extension((void (*)(void)) sqlite3_vec_init);
+}
+*/
+import "C"
+
+// StaticLoader is used in builds tagged sqlite_static_ext, where the graph
+// and vec extensions are compiled directly into the binary and registered
+// via sqlite3_auto_extension instead of sqlite3_load_extension. There is
+// nothing left for the Go side to load.
+//
+// This mode links against libgraph_extension_static.a/libvec_extension_static.a
+// next to this file, the static-archive counterparts of graph_extension.so/
+// vec_extension.so (also not checked into this repo - see .gitignore - and
+// produced by the same out-of-band extension build). Building with
+// -tags sqlite_static_ext without those archives present fails at link time
+// with undefined references to sqlite3_graph_init/sqlite3_vec_init, rather
+// than silently producing a binary that can't load either extension.
+type StaticLoader struct{}
+
+// Prepare always reports ok=false: the extensions are already registered
+// for every new connection by the init() below, so callers should skip
+// LoadExtension entirely.
+func (StaticLoader) Prepare(data []byte, name string) (string, bool, error) {
+	return "", false, nil
+}
+
+func init() {
+	C.register_static_extensions()
+}
+
+// defaultExtensionLoader is used by NewDB when no WithExtensionLoader
+// option is given.
+func defaultExtensionLoader() ExtensionLoader {
+	return StaticLoader{}
+}
@@ -149,6 +149,46 @@ type GraphUpdate struct {
 	Properties map[string]any `json:"properties,omitempty"`
 }
 
+// graphBackingTablesDDL returns the CREATE TABLE statements for a graph's
+// backing tables. Shared by NewGraphDB (so a GraphDB remains usable without
+// a Migrator) and by the "_graph_backing_tables" built-in migration that
+// applies the same DDL for the default "graph" table name when a caller
+// opts into WithMigrations.
+func graphBackingTablesDDL(nodesTable, edgesTable string) string {
+	return fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s(
+			id INTEGER PRIMARY KEY,
+			labels TEXT,
+			properties TEXT
+		);
+		CREATE TABLE IF NOT EXISTS %s(
+			id INTEGER PRIMARY KEY,
+			source INTEGER,
+			target INTEGER,
+			edge_type TEXT,
+			weight REAL,
+			properties TEXT
+		);
+	`, nodesTable, edgesTable)
+}
+
+func init() {
+	nodesTable := "graph_nodes"
+	edgesTable := "graph_edges"
+	RegisterMigration(Migration{
+		ID:          "20240101000000_graph_backing_tables",
+		Description: "create the default graph_nodes/graph_edges backing tables",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, graphBackingTablesDDL(nodesTable, edgesTable))
+			return err
+		},
+		Down: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s; DROP TABLE IF EXISTS %s;", edgesTable, nodesTable))
+			return err
+		},
+	})
+}
+
 // NewGraphDB creates a new graph instance with the given database and table name
 func NewGraphDB(ctx context.Context, db *sql.DB, tableName string) (*GraphDB, error) {
 	g := &GraphDB{
@@ -164,24 +204,10 @@ func NewGraphDB(ctx context.Context, db *sql.DB, tableName string) (*GraphDB, er
 	g.nodesTable = g.tableName + "_nodes"
 	g.edgesTable = g.tableName + "_edges"
 
-	// Create backing tables with proper schema matching the C tests
-	backingTablesQuery := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s(
-			id INTEGER PRIMARY KEY, 
-			labels TEXT, 
-			properties TEXT
-		);
-		CREATE TABLE IF NOT EXISTS %s(
-			id INTEGER PRIMARY KEY, 
-			source INTEGER, 
-			target INTEGER, 
-			edge_type TEXT, 
-			weight REAL, 
-			properties TEXT
-		);
-	`, g.nodesTable, g.edgesTable)
-
-	if _, err := g.db.ExecContext(ctx, backingTablesQuery); err != nil {
+	// Create backing tables with proper schema matching the C tests. Idempotent
+	// and safe to call even if WithMigrations already applied
+	// "20240101000000_graph_backing_tables" for the default table name.
+	if _, err := g.db.ExecContext(ctx, graphBackingTablesDDL(g.nodesTable, g.edgesTable)); err != nil {
 		return nil, fmt.Errorf("failed to create backing tables: %w", err)
 	}
 
@@ -447,6 +473,16 @@ func (g *GraphDB) DeleteNode(ctx context.Context, nodeID int64) error {
 		return fmt.Errorf("failed to delete relationships for node: %w", err)
 	}
 
+	// Also delete any embedding for this node, if the _nodes_vec table has
+	// been created (it's lazily created on first EmbedNode/BatchEmbedNodes call).
+	var vecTableExists bool
+	err = g.db.QueryRowContext(ctx, "SELECT 1 FROM sqlite_master WHERE type IN ('table', 'view') AND name = ?", g.nodesVecTable()).Scan(&vecTableExists)
+	if err == nil && vecTableExists {
+		if _, err := g.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", g.nodesVecTable()), nodeID); err != nil {
+			return fmt.Errorf("failed to delete embedding for node: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,375 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScoredNode is a node returned from SemanticSearch, carrying the scores
+// that produced its rank.
+type ScoredNode struct {
+	Node *Node `json:"node"`
+	// VecScore is the ANN similarity score for this node (0 for nodes that
+	// were only reached via GraphExpand and never matched the k-NN query).
+	VecScore float64 `json:"vec_score"`
+	// GraphProximity is 1/(1+hops) from the nearest seed node reached by
+	// the k-NN step (1 for seed nodes themselves, 0 if GraphExpand was not
+	// requested).
+	GraphProximity float64 `json:"graph_proximity"`
+	// Score is the final ranking score: alpha*VecScore + (1-alpha)*GraphProximity.
+	Score float64 `json:"score"`
+}
+
+// GraphExpandOpts controls post-ANN graph expansion in SemanticSearch.
+type GraphExpandOpts struct {
+	// Hops is how many relationship hops to expand from each seed node.
+	Hops int
+	// RelTypes restricts expansion to these relationship types; empty means
+	// any type.
+	RelTypes []string
+	// Alpha weights the convex combination of vecScore and graphProximity:
+	// score = alpha*vecScore + (1-alpha)*graphProximity. Nil defaults to 0.5;
+	// a pointer (rather than 0 meaning "unset") so Alpha: 0 - rank by graph
+	// proximity alone - is expressible.
+	Alpha *float64
+}
+
+// SemanticSearchOpts configures GraphDB.SemanticSearch.
+type SemanticSearchOpts struct {
+	TopK           int
+	Namespace      string
+	LabelFilter    []string
+	PropertyFilter map[string]any
+	GraphExpand    *GraphExpandOpts
+}
+
+// nodesVecTable returns the name of this graph's node-embedding vec0
+// virtual table.
+func (g *GraphDB) nodesVecTable() string {
+	return g.tableName + "_nodes_vec"
+}
+
+// ensureNodesVecTable lazily creates the _nodes_vec virtual table sized to
+// dimensions, the dimensionality of the first vector ever embedded. Like
+// NewVectorDB, GraphDB does not know the embedding dimensionality up front,
+// so the table is created on first use rather than in NewGraphDB.
+func (g *GraphDB) ensureNodesVecTable(ctx context.Context, dimensions int) error {
+	query := fmt.Sprintf(
+		"CREATE VIRTUAL TABLE IF NOT EXISTS %s USING vec0(embedding float[%d], +namespace TEXT)",
+		g.nodesVecTable(), dimensions)
+	if _, err := g.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create node embedding table: %w", err)
+	}
+	return nil
+}
+
+// EmbedNode upserts the embedding for nodeID into the node's vec0 table,
+// tagging it with namespace so SemanticSearch can scope queries to a
+// subset of embeddings (e.g. "titles" vs "summaries").
+func (g *GraphDB) EmbedNode(ctx context.Context, nodeID int64, vector []float32, namespace string) error {
+	if err := g.ensureNodesVecTable(ctx, len(vector)); err != nil {
+		return err
+	}
+
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin embed transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := embedNodeTx(ctx, tx, g.nodesVecTable(), nodeID, vector, namespace); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit embed transaction: %w", err)
+	}
+	return nil
+}
+
+// embedNodeTx performs the delete+insert upsert for a single node embedding
+// within an already-open transaction, shared by EmbedNode and BatchEmbedNodes.
+func embedNodeTx(ctx context.Context, tx *sql.Tx, vecTable string, nodeID int64, vector []float32, namespace string) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", vecTable), nodeID); err != nil {
+		return fmt.Errorf("failed to clear existing embedding for node %d: %w", nodeID, err)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s(rowid, embedding, namespace) VALUES (?, ?, ?)", vecTable)
+	if _, err := tx.ExecContext(ctx, query, nodeID, float32sToBytes(vector), namespace); err != nil {
+		return fmt.Errorf("failed to embed node %d: %w", nodeID, err)
+	}
+	return nil
+}
+
+// NodeEmbedding is one row of a BatchEmbedNodes call.
+type NodeEmbedding struct {
+	NodeID    int64
+	Vector    []float32
+	Namespace string
+}
+
+// BatchEmbedNodes writes many node embeddings inside a single transaction,
+// for bulk ingest performance.
+func (g *GraphDB) BatchEmbedNodes(ctx context.Context, embeddings []NodeEmbedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+
+	if err := g.ensureNodesVecTable(ctx, len(embeddings[0].Vector)); err != nil {
+		return err
+	}
+
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin batch embed transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, e := range embeddings {
+		if err := embedNodeTx(ctx, tx, g.nodesVecTable(), e.NodeID, e.Vector, e.Namespace); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch embed transaction: %w", err)
+	}
+	return nil
+}
+
+// SemanticSearch runs an ANN k-NN lookup against the node embeddings,
+// optionally filtered by label/property and expanded over the graph
+// structure. When opts.GraphExpand is set, nodes reachable within
+// opts.GraphExpand.Hops of a k-NN match are pulled in too and the final
+// ranking combines vector similarity with graph proximity.
+func (g *GraphDB) SemanticSearch(ctx context.Context, query []float32, opts SemanticSearchOpts) ([]*ScoredNode, error) {
+	if opts.TopK <= 0 {
+		opts.TopK = 10
+	}
+
+	var vecTableExists bool
+	err := g.db.QueryRowContext(ctx, "SELECT 1 FROM sqlite_master WHERE type IN ('table', 'view') AND name = ?", g.nodesVecTable()).Scan(&vecTableExists)
+	if err != nil || !vecTableExists {
+		// No node has ever been embedded, so the _nodes_vec table (lazily
+		// created by EmbedNode/BatchEmbedNodes) doesn't exist yet; that's an
+		// empty result, not an error, matching DeleteVector's equivalent
+		// check for the FTS5 shadow table in hybrid_search.go.
+		return nil, nil
+	}
+
+	queryBytes := float32sToBytes(query)
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT rowid, distance
+		FROM %s
+		WHERE embedding MATCH ? AND namespace = ?
+		ORDER BY distance
+		LIMIT ?
+	`, g.nodesVecTable())
+
+	rows, err := g.db.QueryContext(ctx, sqlQuery, queryBytes, opts.Namespace, opts.TopK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run semantic search: %w", err)
+	}
+	defer rows.Close()
+
+	type seed struct {
+		nodeID   int64
+		distance float64
+	}
+	var seeds []seed
+	for rows.Next() {
+		var s seed
+		if err := rows.Scan(&s.nodeID, &s.distance); err != nil {
+			return nil, fmt.Errorf("failed to scan semantic search row: %w", err)
+		}
+		seeds = append(seeds, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// hops[nodeID] is the shortest known hop distance from any seed; seeds
+	// themselves are hop 0.
+	hops := make(map[int64]int, len(seeds))
+	vecScores := make(map[int64]float64, len(seeds))
+	for _, s := range seeds {
+		hops[s.nodeID] = 0
+		vecScores[s.nodeID] = 1 / (1 + s.distance)
+	}
+
+	alpha := 0.5
+	if opts.GraphExpand != nil {
+		alpha = resolveAlpha(opts.GraphExpand.Alpha)
+		if err := g.expandHops(ctx, hops, opts.GraphExpand); err != nil {
+			return nil, err
+		}
+	}
+
+	candidateIDs := make([]int64, 0, len(hops))
+	for id := range hops {
+		candidateIDs = append(candidateIDs, id)
+	}
+
+	nodes, err := g.nodesByID(ctx, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*ScoredNode
+	for _, n := range nodes {
+		if !nodeMatchesFilter(n, opts.LabelFilter, opts.PropertyFilter) {
+			continue
+		}
+
+		graphProximity := 0.0
+		if h, ok := hops[n.ID]; ok {
+			graphProximity = 1 / float64(1+h)
+		}
+		vecScore := vecScores[n.ID]
+
+		results = append(results, &ScoredNode{
+			Node:           n,
+			VecScore:       vecScore,
+			GraphProximity: graphProximity,
+			Score:          alpha*vecScore + (1-alpha)*graphProximity,
+		})
+	}
+
+	sortScoredNodesDesc(results)
+	return results, nil
+}
+
+// resolveAlpha applies GraphExpandOpts.Alpha's nil-means-0.5 default.
+func resolveAlpha(alpha *float64) float64 {
+	if alpha != nil {
+		return *alpha
+	}
+	return 0.5
+}
+
+// expandHops performs a bounded BFS from every seed in hops over the edges
+// table (in both directions), recording the shortest hop distance to each
+// newly discovered node, up to opts.Hops deep.
+func (g *GraphDB) expandHops(ctx context.Context, hops map[int64]int, opts *GraphExpandOpts) error {
+	frontier := make([]int64, 0, len(hops))
+	for id, h := range hops {
+		if h == 0 {
+			frontier = append(frontier, id)
+		}
+	}
+
+	typeFilter := ""
+	var typeArgs []any
+	if len(opts.RelTypes) > 0 {
+		placeholders := make([]string, len(opts.RelTypes))
+		for i, t := range opts.RelTypes {
+			placeholders[i] = "?"
+			typeArgs = append(typeArgs, t)
+		}
+		typeFilter = fmt.Sprintf(" AND edge_type IN (%s)", strings.Join(placeholders, ", "))
+	}
+
+	for depth := 1; depth <= opts.Hops && len(frontier) > 0; depth++ {
+		var next []int64
+		for _, nodeID := range frontier {
+			query := fmt.Sprintf("SELECT source, target FROM %s WHERE (source = ? OR target = ?)%s", g.edgesTable, typeFilter)
+			args := append([]any{nodeID, nodeID}, typeArgs...)
+
+			rows, err := g.db.QueryContext(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to expand graph neighborhood: %w", err)
+			}
+
+			for rows.Next() {
+				var source, target int64
+				if err := rows.Scan(&source, &target); err != nil {
+					rows.Close()
+					return fmt.Errorf("failed to scan edge row: %w", err)
+				}
+				neighbor := target
+				if neighbor == nodeID {
+					neighbor = source
+				}
+				if _, seen := hops[neighbor]; !seen {
+					hops[neighbor] = depth
+					next = append(next, neighbor)
+				}
+			}
+			if err := rows.Err(); err != nil {
+				rows.Close()
+				return err
+			}
+			rows.Close()
+		}
+		frontier = next
+	}
+
+	return nil
+}
+
+// nodesByID fetches and decodes full Node rows for the given ids.
+func (g *GraphDB) nodesByID(ctx context.Context, ids []int64) ([]*Node, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := fmt.Sprintf("SELECT id, labels, properties FROM %s WHERE id IN (%s)", g.nodesTable, strings.Join(placeholders, ", "))
+	rows, err := g.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nodes by id: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []*Node
+	for rows.Next() {
+		var id int64
+		var labelsJSON, propertiesJSON string
+		if err := rows.Scan(&id, &labelsJSON, &propertiesJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan node row: %w", err)
+		}
+		labels, properties := decodeLabelsAndProps(map[string]any{"x_labels": labelsJSON, "x_properties": propertiesJSON})
+		nodes = append(nodes, &Node{ID: id, Labels: labels, Properties: properties})
+	}
+	return nodes, rows.Err()
+}
+
+// nodeMatchesFilter applies the same label/property equality semantics as
+// FindNodes, reused here so SemanticSearch filters consistently.
+func nodeMatchesFilter(n *Node, labels []string, properties map[string]any) bool {
+	for _, want := range labels {
+		found := false
+		for _, got := range n.Labels {
+			if got == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for k, v := range properties {
+		got, ok := n.Properties[k]
+		if !ok || got != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortScoredNodesDesc(results []*ScoredNode) {
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+}
@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveAlphaDefaultsToHalf(t *testing.T) {
+	if got := resolveAlpha(nil); got != 0.5 {
+		t.Fatalf("expected nil Alpha to default to 0.5, got %v", got)
+	}
+}
+
+// TestResolveAlphaZeroIsExplicit verifies Alpha: 0 (rank by graph proximity
+// alone) survives resolution instead of being treated as "unset" and
+// overridden back to the 0.5 default.
+func TestResolveAlphaZeroIsExplicit(t *testing.T) {
+	zero := 0.0
+	if got := resolveAlpha(&zero); got != 0 {
+		t.Fatalf("expected explicit Alpha: 0 to resolve to 0, got %v", got)
+	}
+}
+
+func TestResolveAlphaPassesThroughNonZero(t *testing.T) {
+	quarter := 0.25
+	if got := resolveAlpha(&quarter); got != 0.25 {
+		t.Fatalf("expected explicit Alpha to pass through unchanged, got %v", got)
+	}
+}
+
+// TestSemanticSearchBeforeAnyEmbedReturnsEmpty verifies SemanticSearch
+// returns an empty result rather than a raw "no such table" SQLite error
+// when called before any node has ever been embedded (the _nodes_vec table
+// is only lazily created by EmbedNode/BatchEmbedNodes).
+func TestSemanticSearchBeforeAnyEmbedReturnsEmpty(t *testing.T) {
+	g := newTestGraphDB(t)
+
+	results, err := g.SemanticSearch(context.Background(), []float32{1, 2, 3}, SemanticSearchOpts{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if results != nil {
+		t.Fatalf("expected nil results, got %+v", results)
+	}
+}
@@ -0,0 +1,320 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FusionMode selects how VectorDB.HybridSearch combines vector similarity
+// and BM25 keyword rankings into one score.
+type FusionMode int
+
+const (
+	// Weighted linearly combines min-max normalized vector and BM25 scores:
+	// score = alpha*vecScore + (1-alpha)*textScore.
+	Weighted FusionMode = iota
+	// RRF (Reciprocal Rank Fusion) combines ranks instead of raw scores:
+	// score = sum(1/(k+rank_i)) over the result lists a document appears
+	// in. Robust to the two searches' scores living on incomparable
+	// scales.
+	RRF
+)
+
+// HybridOptions configures VectorDB.HybridSearch.
+type HybridOptions struct {
+	// K is the maximum number of results to return. Defaults to 10.
+	K int
+	// Alpha weights the vector-search side of a Weighted fusion:
+	// score = alpha*vecScore + (1-alpha)*textScore. Ignored by RRF. Nil
+	// defaults to 0.5; a pointer (rather than 0 meaning "unset") so
+	// Alpha: 0 - rank by keyword score alone - is expressible.
+	Alpha *float64
+	// Fusion selects how the vector and keyword result lists are combined.
+	// Defaults to Weighted.
+	Fusion FusionMode
+	// RRFK is the rank-fusion constant k in score = sum(1/(k+rank_i)).
+	// Defaults to 60. Ignored by Weighted.
+	RRFK int
+}
+
+// ScoredResult is one row of a VectorDB.HybridSearch result, carrying the
+// scores that produced its rank.
+type ScoredResult struct {
+	ID int64 `json:"id"`
+	// VecScore is the normalized vector similarity score (0 if the row was
+	// only matched by keyword search).
+	VecScore float64 `json:"vec_score"`
+	// TextScore is the normalized BM25 keyword score (0 if the row was
+	// only matched by vector search).
+	TextScore float64 `json:"text_score"`
+	// Score is the fused ranking score; see HybridOptions.Fusion.
+	Score float64 `json:"score"`
+}
+
+// tagsString flattens a Document's metadata["tags"] field, however it was
+// decoded from JSON ([]string or []any of strings), into the
+// space-separated form the FTS5 tags column expects.
+func tagsString(metadata map[string]any) string {
+	raw, ok := metadata["tags"]
+	if !ok {
+		return ""
+	}
+
+	var tags []string
+	switch v := raw.(type) {
+	case []string:
+		tags = v
+	case []any:
+		for _, t := range v {
+			if s, ok := t.(string); ok {
+				tags = append(tags, s)
+			}
+		}
+	}
+
+	return strings.Join(tags, " ")
+}
+
+// ftsTable returns the name of this VectorDB's FTS5 shadow table.
+func (vs *VectorDB) ftsTable() string {
+	return vs.tableName + "_fts"
+}
+
+// ensureFTSTable lazily creates the FTS5 shadow table used by IndexText and
+// HybridSearch, the same way the vec0 table's auxiliary tables elsewhere in
+// this package are created on first use.
+func (vs *VectorDB) ensureFTSTable(ctx context.Context) error {
+	query := fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(content, tags, context_id)", vs.ftsTable())
+	if _, err := vs.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create fts table: %w", err)
+	}
+	return nil
+}
+
+// IndexText upserts the FTS5 shadow row for id, keeping keyword search in
+// sync with content indexed outside of vs's own embedding table (e.g. a
+// DocumentStore's docs table).
+func (vs *VectorDB) IndexText(ctx context.Context, id uint64, content, tags, contextID string) error {
+	if err := vs.ensureFTSTable(ctx); err != nil {
+		return err
+	}
+	return indexTextTx(ctx, vs.db, vs.ftsTable(), id, content, tags, contextID)
+}
+
+// indexTextTx performs the delete+insert upsert for a single FTS row,
+// against either *sql.DB or *sql.Tx, mirroring embedNodeTx's upsert
+// pattern for the vec0 tables elsewhere in this package.
+func indexTextTx(ctx context.Context, exec interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}, ftsTable string, id uint64, content, tags, contextID string) error {
+	if _, err := exec.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", ftsTable), id); err != nil {
+		return fmt.Errorf("failed to clear existing fts row for %d: %w", id, err)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s(rowid, content, tags, context_id) VALUES (?, ?, ?, ?)", ftsTable)
+	if _, err := exec.ExecContext(ctx, query, id, content, tags, contextID); err != nil {
+		return fmt.Errorf("failed to index text for %d: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteVector removes id's embedding, and its FTS shadow row if the FTS
+// table has been created (it's lazily created on first IndexText call).
+func (vs *VectorDB) DeleteVector(ctx context.Context, id uint64) error {
+	if _, err := vs.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", vs.tableName), id); err != nil {
+		return fmt.Errorf("failed to delete vector: %w", err)
+	}
+
+	var ftsTableExists bool
+	err := vs.db.QueryRowContext(ctx, "SELECT 1 FROM sqlite_master WHERE type IN ('table', 'view') AND name = ?", vs.ftsTable()).Scan(&ftsTableExists)
+	if err == nil && ftsTableExists {
+		if _, err := vs.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", vs.ftsTable()), id); err != nil {
+			return fmt.Errorf("failed to delete fts row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// textHit is one row of the BM25 keyword search side of HybridSearch.
+type textHit struct {
+	id   int64
+	bm25 float64
+}
+
+// searchText runs the FTS5 side of HybridSearch, returning up to k rows
+// ordered by bm25 (most relevant first). bm25() returns a more-negative
+// value for a better match, so ordering is ascending.
+func (vs *VectorDB) searchText(ctx context.Context, query string, k int) ([]textHit, error) {
+	if err := vs.ensureFTSTable(ctx); err != nil {
+		return nil, err
+	}
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT rowid, bm25(%s)
+		FROM %s
+		WHERE %s MATCH ?
+		ORDER BY bm25(%s)
+		LIMIT ?
+	`, vs.ftsTable(), vs.ftsTable(), vs.ftsTable(), vs.ftsTable())
+
+	rows, err := vs.db.QueryContext(ctx, sqlQuery, query, k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run keyword search: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []textHit
+	for rows.Next() {
+		var h textHit
+		if err := rows.Scan(&h.id, &h.bm25); err != nil {
+			return nil, fmt.Errorf("failed to scan keyword search row: %w", err)
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
+
+// HybridSearch combines vector similarity and BM25 keyword search over
+// VectorDB's embedding table and its FTS5 shadow table, fusing the two
+// ranked lists per opts.Fusion. Embedding the query text into a vector is
+// the caller's responsibility - query is matched against the FTS5 table
+// as-is, and queryVector against the vec0 table.
+func (vs *VectorDB) HybridSearch(ctx context.Context, query string, queryVector []float32, opts HybridOptions) ([]ScoredResult, error) {
+	k := opts.K
+	if k <= 0 {
+		k = 10
+	}
+	alpha := resolveHybridAlpha(opts.Alpha)
+	rrfK := opts.RRFK
+	if rrfK <= 0 {
+		rrfK = 60
+	}
+
+	type vecSearchResult struct {
+		hits []VectorResult
+		err  error
+	}
+	type textSearchResult struct {
+		hits []textHit
+		err  error
+	}
+	vecCh := make(chan vecSearchResult, 1)
+	textCh := make(chan textSearchResult, 1)
+
+	go func() {
+		hits, err := vs.SearchSimilarVectors(ctx, queryVector, SearchOptions{TopK: k})
+		vecCh <- vecSearchResult{hits: hits, err: err}
+	}()
+	go func() {
+		hits, err := vs.searchText(ctx, query, k)
+		textCh <- textSearchResult{hits: hits, err: err}
+	}()
+
+	vecRes, textRes := <-vecCh, <-textCh
+	if vecRes.err != nil {
+		return nil, vecRes.err
+	}
+	if textRes.err != nil {
+		return nil, textRes.err
+	}
+
+	switch opts.Fusion {
+	case RRF:
+		return fuseRRF(vecRes.hits, textRes.hits, rrfK, k), nil
+	default:
+		return fuseWeighted(vecRes.hits, textRes.hits, alpha, k), nil
+	}
+}
+
+// resolveHybridAlpha applies HybridOptions.Alpha's nil-means-0.5 default.
+func resolveHybridAlpha(alpha *float64) float64 {
+	if alpha != nil {
+		return *alpha
+	}
+	return 0.5
+}
+
+// fuseWeighted combines min-max normalized vector and BM25 scores via
+// score = alpha*vecScore + (1-alpha)*textScore.
+func fuseWeighted(vecHits []VectorResult, textHits []textHit, alpha float64, k int) []ScoredResult {
+	byID := make(map[int64]*ScoredResult)
+
+	for _, h := range vecHits {
+		byID[h.ID] = &ScoredResult{ID: h.ID, VecScore: h.Score}
+	}
+
+	minBM25, maxBM25 := 0.0, 0.0
+	for i, h := range textHits {
+		if i == 0 || h.bm25 < minBM25 {
+			minBM25 = h.bm25
+		}
+		if i == 0 || h.bm25 > maxBM25 {
+			maxBM25 = h.bm25
+		}
+	}
+	spread := maxBM25 - minBM25
+
+	for _, h := range textHits {
+		// bm25() is more negative for a better match, so invert before
+		// min-max scaling into [0, 1] with 1 as the best match.
+		textScore := 1.0
+		if spread > 0 {
+			textScore = (maxBM25 - h.bm25) / spread
+		}
+
+		r, ok := byID[h.id]
+		if !ok {
+			r = &ScoredResult{ID: h.id}
+			byID[h.id] = r
+		}
+		r.TextScore = textScore
+	}
+
+	results := make([]ScoredResult, 0, len(byID))
+	for _, r := range byID {
+		r.Score = alpha*r.VecScore + (1-alpha)*r.TextScore
+		results = append(results, *r)
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// fuseRRF combines the two ranked lists via Reciprocal Rank Fusion:
+// score = sum(1/(rrfK+rank_i)), rank_i starting at 1.
+func fuseRRF(vecHits []VectorResult, textHits []textHit, rrfK, k int) []ScoredResult {
+	byID := make(map[int64]*ScoredResult)
+
+	for rank, h := range vecHits {
+		r := &ScoredResult{ID: h.ID, VecScore: h.Score}
+		r.Score += 1 / float64(rrfK+rank+1)
+		byID[h.ID] = r
+	}
+
+	for rank, h := range textHits {
+		r, ok := byID[h.id]
+		if !ok {
+			r = &ScoredResult{ID: h.id}
+			byID[h.id] = r
+		}
+		r.Score += 1 / float64(rrfK+rank+1)
+	}
+
+	results := make([]ScoredResult, 0, len(byID))
+	for _, r := range byID {
+		results = append(results, *r)
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
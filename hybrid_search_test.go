@@ -0,0 +1,44 @@
+package sqlite
+
+import "testing"
+
+func TestResolveHybridAlphaDefaultsToHalf(t *testing.T) {
+	if got := resolveHybridAlpha(nil); got != 0.5 {
+		t.Fatalf("expected nil Alpha to default to 0.5, got %v", got)
+	}
+}
+
+// TestResolveHybridAlphaZeroIsExplicit verifies Alpha: 0 (rank by keyword
+// score alone) survives resolution instead of being treated as "unset" and
+// overridden back to the 0.5 default.
+func TestResolveHybridAlphaZeroIsExplicit(t *testing.T) {
+	zero := 0.0
+	if got := resolveHybridAlpha(&zero); got != 0 {
+		t.Fatalf("expected explicit Alpha: 0 to resolve to 0, got %v", got)
+	}
+}
+
+func TestResolveHybridAlphaPassesThroughNonZero(t *testing.T) {
+	quarter := 0.25
+	if got := resolveHybridAlpha(&quarter); got != 0.25 {
+		t.Fatalf("expected explicit Alpha to pass through unchanged, got %v", got)
+	}
+}
+
+// TestFuseWeightedAlphaZeroRanksByTextScoreAlone verifies fuseWeighted
+// actually honors alpha=0 end to end: a vector-only hit with a high
+// VecScore must not outrank a keyword-only hit once alpha zeroes out the
+// vector term.
+func TestFuseWeightedAlphaZeroRanksByTextScoreAlone(t *testing.T) {
+	vecHits := []VectorResult{{ID: 1, Score: 0.99}}
+	textHits := []textHit{{id: 2, bm25: -5}}
+
+	results := fuseWeighted(vecHits, textHits, 0, 10)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].ID != 2 {
+		t.Fatalf("expected the keyword-only hit to rank first with alpha=0, got ID %d first", results[0].ID)
+	}
+}
@@ -0,0 +1,373 @@
+package sqlite
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Migration describes a single versioned schema change. IDs are expected to
+// be lexicographically sortable (e.g. a timestamp prefix such as
+// "20240115093000_add_edge_weight_index") so that Migrator can apply them in
+// a deterministic order.
+type Migration struct {
+	ID          string
+	Description string
+	Up          func(ctx context.Context, tx *sql.Tx) error
+	Down        func(ctx context.Context, tx *sql.Tx) error
+}
+
+// MigrationStatus reports whether a registered migration has been applied.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+var (
+	migrationsMu         sync.Mutex
+	registeredMigrations = map[string]Migration{}
+)
+
+// RegisterMigration adds a migration to the global registry. It is intended
+// to be called from package init() functions, mirroring how the graph and
+// vec extensions register themselves. Registering the same ID twice panics,
+// since that indicates a programming error rather than a runtime condition.
+func RegisterMigration(m Migration) {
+	if m.ID == "" {
+		panic("sqlite: migration registered with empty ID")
+	}
+	if m.Up == nil {
+		panic(fmt.Sprintf("sqlite: migration %s has no Up function", m.ID))
+	}
+
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	if _, exists := registeredMigrations[m.ID]; exists {
+		panic(fmt.Sprintf("sqlite: migration %s already registered", m.ID))
+	}
+	registeredMigrations[m.ID] = m
+}
+
+// checksum returns a short content hash of the migration's description, used
+// purely to detect a registered migration's SQL having drifted since it was
+// applied. It is advisory only; Migrator does not refuse to run on mismatch.
+func (m Migration) checksum() string {
+	sum := sha256.Sum256([]byte(m.ID + "\x00" + m.Description))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Migrator manages the schema_migrations bookkeeping table and applies
+// registered migrations against a *sql.DB in ID order.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a Migrator over the given database, seeded with the
+// globally registered migrations plus any extra migrations passed in. Extra
+// migrations are merged with the global registry and re-sorted by ID.
+func NewMigrator(db *sql.DB, extra ...Migration) *Migrator {
+	migrationsMu.Lock()
+	all := make([]Migration, 0, len(registeredMigrations)+len(extra))
+	for _, m := range registeredMigrations {
+		all = append(all, m)
+	}
+	migrationsMu.Unlock()
+	all = append(all, extra...)
+
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	return &Migrator{db: db, migrations: all}
+}
+
+const createSchemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations(
+	id TEXT PRIMARY KEY,
+	applied_at TIMESTAMP NOT NULL,
+	checksum TEXT NOT NULL
+);`
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	if _, err := m.db.ExecContext(ctx, createSchemaMigrationsTable); err != nil {
+		return fmt.Errorf("migrator: failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) appliedIDs(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT id, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("migrator: failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]time.Time)
+	for rows.Next() {
+		var id string
+		var appliedAt time.Time
+		if err := rows.Scan(&id, &appliedAt); err != nil {
+			return nil, fmt.Errorf("migrator: failed to scan schema_migrations row: %w", err)
+		}
+		applied[id] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// Up applies all pending migrations, in ID order, each inside its own
+// transaction so a failure partway through leaves the schema at the last
+// successfully applied migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.appliedIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.ID]; ok {
+			continue
+		}
+
+		err := m.runInTx(ctx, migration, func(ctx context.Context, tx *sql.Tx) error {
+			if err := migration.Up(ctx, tx); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx,
+				"INSERT INTO schema_migrations(id, applied_at, checksum) VALUES (?, ?, ?)",
+				migration.ID, time.Now().UTC(), migration.checksum())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migrator: migration %s failed: %w", migration.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Down rolls back the given number of applied migrations, most recently
+// applied first.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return nil
+	}
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.appliedIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Walk registered migrations in reverse ID order, rolling back the
+	// applied ones until we've done `steps` of them.
+	reversed := make([]Migration, len(m.migrations))
+	copy(reversed, m.migrations)
+	sort.Slice(reversed, func(i, j int) bool { return reversed[i].ID > reversed[j].ID })
+
+	done := 0
+	for _, migration := range reversed {
+		if done >= steps {
+			break
+		}
+		if _, ok := applied[migration.ID]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return fmt.Errorf("migrator: migration %s has no Down function", migration.ID)
+		}
+
+		err := m.runInTx(ctx, migration, func(ctx context.Context, tx *sql.Tx) error {
+			if err := migration.Down(ctx, tx); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE id = ?", migration.ID)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migrator: rollback of %s failed: %w", migration.ID, err)
+		}
+		done++
+	}
+
+	return nil
+}
+
+func (m *Migrator) runInTx(ctx context.Context, migration Migration, fn func(context.Context, *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(ctx, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Status reports, for every registered migration, whether it has been
+// applied and when.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.ID]
+		statuses = append(statuses, MigrationStatus{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// migrationFromSQL splits a single .sql file's contents on the
+// "-- +migrate Up" / "-- +migrate Down" markers, in the style of
+// goose/xormigrate embedded migrations.
+func migrationFromSQL(id, contents string) (Migration, error) {
+	const upMarker = "-- +migrate Up"
+	const downMarker = "-- +migrate Down"
+
+	upIdx := strings.Index(contents, upMarker)
+	if upIdx == -1 {
+		return Migration{}, fmt.Errorf("migration %s: missing %q marker", id, upMarker)
+	}
+
+	downIdx := strings.Index(contents, downMarker)
+	var upSQL, downSQL string
+	if downIdx == -1 {
+		upSQL = contents[upIdx+len(upMarker):]
+	} else {
+		upSQL = contents[upIdx+len(upMarker) : downIdx]
+		downSQL = contents[downIdx+len(downMarker):]
+	}
+
+	upSQL = strings.TrimSpace(upSQL)
+	downSQL = strings.TrimSpace(downSQL)
+
+	return Migration{
+		ID:          id,
+		Description: firstCommentLine(contents),
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			if upSQL == "" {
+				return nil
+			}
+			_, err := tx.ExecContext(ctx, upSQL)
+			return err
+		},
+		Down: func(ctx context.Context, tx *sql.Tx) error {
+			if downSQL == "" {
+				return nil
+			}
+			_, err := tx.ExecContext(ctx, downSQL)
+			return err
+		},
+	}, nil
+}
+
+// firstCommentLine returns the first "-- " comment line of a migration file,
+// used as its Description when loading from embed.FS.
+func firstCommentLine(contents string) string {
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "-- +migrate") {
+			continue
+		}
+		if strings.HasPrefix(line, "--") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "--"))
+		}
+	}
+	return ""
+}
+
+// LoadMigrationsFS reads every "*.sql" file from the given embed.FS and
+// registers one Migration per file, using the filename (without extension)
+// as the migration ID. This lets callers ship migrations as plain .sql
+// files with "-- +migrate Up" / "-- +migrate Down" sections instead of Go
+// functions.
+func LoadMigrationsFS(fsys embed.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrations: failed to read embedded migrations: %w", err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrations: failed to read %s: %w", entry.Name(), err)
+		}
+
+		id := strings.TrimSuffix(entry.Name(), ".sql")
+		migration, err := migrationFromSQL(id, string(data))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].ID < migrations[j].ID })
+	return migrations, nil
+}
+
+// withMigrationsOption is the functional option applied by NewDB to run
+// pending migrations on open. See WithMigrations.
+type dbOptions struct {
+	runMigrations   bool
+	extraMigrations []Migration
+	extensionLoader ExtensionLoader
+}
+
+// DBOption configures optional behavior of NewDB.
+type DBOption func(*dbOptions)
+
+// WithMigrations instructs NewDB to run all pending migrations (the global
+// registry plus any extras passed here) immediately after opening the
+// database, before returning it to the caller.
+func WithMigrations(extra ...Migration) DBOption {
+	return func(o *dbOptions) {
+		o.runMigrations = true
+		o.extraMigrations = extra
+	}
+}
+
+// WithExtensionLoader overrides how NewDB prepares the embedded graph and
+// vec extensions for loading. The default writes them to a content-addressed
+// temp file; see ExtensionLoader for alternatives such as MemfdLoader.
+func WithExtensionLoader(loader ExtensionLoader) DBOption {
+	return func(o *dbOptions) {
+		o.extensionLoader = loader
+	}
+}
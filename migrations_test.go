@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// TestMigratorUpRecordsBookkeepingAtomically verifies that a migration whose
+// Up function fails after altering the schema leaves neither the schema
+// change nor the schema_migrations row committed, i.e. the migration body
+// and its bookkeeping insert share one transaction.
+func TestMigratorUpRecordsBookkeepingAtomically(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	failAfterDDL := Migration{
+		ID:          "0001_fails_after_ddl",
+		Description: "creates a table, then fails",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, "CREATE TABLE widgets(id INTEGER PRIMARY KEY)"); err != nil {
+				return err
+			}
+			return errors.New("boom")
+		},
+	}
+
+	m := NewMigrator(db, failAfterDDL)
+	if err := m.Up(ctx); err == nil {
+		t.Fatal("expected Up to fail")
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'widgets'").Scan(&count); err != nil {
+		t.Fatalf("failed to check for widgets table: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected failed migration's DDL to be rolled back, but widgets table exists")
+	}
+
+	applied, err := m.appliedIDs(ctx)
+	if err != nil {
+		t.Fatalf("failed to query applied migrations: %v", err)
+	}
+	if _, ok := applied[failAfterDDL.ID]; ok {
+		t.Fatal("expected failed migration to not be recorded as applied")
+	}
+}
+
+// TestMigratorUpIsIdempotentOnSuccess is a sanity check that a clean Up run
+// records the migration exactly once and a second Up call doesn't re-apply it.
+func TestMigratorUpIsIdempotentOnSuccess(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	runs := 0
+	migration := Migration{
+		ID:          "0001_creates_widgets",
+		Description: "creates a table",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			runs++
+			_, err := tx.ExecContext(ctx, "CREATE TABLE widgets(id INTEGER PRIMARY KEY)")
+			return err
+		},
+	}
+
+	m := NewMigrator(db, migration)
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("first Up failed: %v", err)
+	}
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("second Up failed: %v", err)
+	}
+	if runs != 1 {
+		t.Fatalf("expected migration Up to run once, ran %d times", runs)
+	}
+}
@@ -85,10 +85,14 @@ type InsertOperation struct {
 type UpdateOperation struct {
 	Set   []map[string]any `json:"set"`
 	Where string           `json:"where"`
+	// WhereArgs binds any `?` placeholders in Where, in order.
+	WhereArgs []any `json:"where_args"`
 }
 
 type DeleteOperation struct {
 	Where string
+	// WhereArgs binds any `?` placeholders in Where, in order.
+	WhereArgs []any `json:"where_args"`
 }
 
 type BatchOperations struct {
@@ -3,8 +3,10 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/binary"
 	"fmt"
-	"unsafe"
+	"math"
+	"strings"
 
 	"github.com/gogo-agent/jsonschema"
 )
@@ -118,32 +120,286 @@ var (
 
 // VectorDB represents a vector database for embeddings
 type VectorDB struct {
-	db         *sql.DB
-	tableName  string
-	dimensions int
+	db              *sql.DB
+	tableName       string
+	dimensions      int
+	metric          DistanceMetric
+	index           IndexOptions
+	insertBatchSize int
+	// columns holds every named vec0 column this table was created with,
+	// keyed by name. A VectorDB created without WithColumns has exactly
+	// one entry, "embedding", which InsertVector/SearchSimilarVectors
+	// delegate to.
+	columns map[string]VectorColumn
 }
 
+// VectorColumn describes one named embedding column of a multi-vector
+// VectorDB, e.g. separate "title" and "body" columns with their own
+// dimensionality, metric and quantization.
+type VectorColumn struct {
+	Name         string
+	Dimensions   int
+	Metric       DistanceMetric
+	Quantization Quantization
+}
+
+const defaultVectorColumn = "embedding"
+
 // VectorResult represents a vector search result
 type VectorResult struct {
 	ID       int64     `json:"id"`
 	Vector   []float32 `json:"vector"`
 	Distance float64   `json:"distance"`
+	// Score is Distance normalized to a 0..1 similarity score per Metric,
+	// where 1 is most similar. See DistanceMetric.similarity.
+	Score float64 `json:"score"`
+}
+
+// DistanceMetric selects the vec0 distance metric a VectorDB's embedding
+// column is compared with, and the formula used to normalize that column's
+// raw distance into a comparable 0..1 similarity score.
+type DistanceMetric int
+
+const (
+	// MetricDefault defers to vec0's own default (MetricL2) when set on a
+	// VectorDB, or to the VectorDB's configured metric when set on a
+	// SearchOptions - it is never a metric in its own right.
+	MetricDefault DistanceMetric = iota
+	// MetricL2 is Euclidean distance.
+	MetricL2
+	// MetricCosine is cosine distance (1 - cosine similarity).
+	MetricCosine
+	// MetricDot is inner-product distance.
+	MetricDot
+)
+
+// resolve maps MetricDefault to MetricL2, vec0's own default metric.
+func (m DistanceMetric) resolve() DistanceMetric {
+	if m == MetricDefault {
+		return MetricL2
+	}
+	return m
+}
+
+// Quantization selects the storage representation of a VectorDB's embedding
+// column, trading precision for space.
+type Quantization int
+
+const (
+	// QuantizationNone stores full-precision float32 components.
+	QuantizationNone Quantization = iota
+	// QuantizationInt8 stores 8-bit integer components.
+	QuantizationInt8
+	// QuantizationBinary stores a single bit per component.
+	QuantizationBinary
+)
+
+// vecElementType returns the vec0 column element type for q, e.g. "float",
+// "int8", or "bit".
+func (q Quantization) vecElementType() string {
+	switch q {
+	case QuantizationInt8:
+		return "int8"
+	case QuantizationBinary:
+		return "bit"
+	default:
+		return "float"
+	}
+}
+
+// IndexKind selects the nearest-neighbor index strategy for a VectorDB's
+// vec0 table.
+type IndexKind int
+
+const (
+	// IndexFlat performs an exact brute-force scan. vec0's default.
+	IndexFlat IndexKind = iota
+	// IndexIVF is an inverted-file index, analogous to pgvector's ivfflat.
+	IndexIVF
+	// IndexHNSW is a hierarchical navigable small world graph index,
+	// analogous to pgvector's hnsw.
+	IndexHNSW
+)
+
+// IndexOptions configures the approximate nearest-neighbor index and
+// storage representation of a VectorDB's embedding column. The zero value
+// is an exact, full-precision flat scan.
+type IndexOptions struct {
+	// Kind selects the index strategy. Defaults to IndexFlat.
+	Kind IndexKind
+	// Lists is the number of inverted-file partitions built for IndexIVF.
+	// Defaults to 100.
+	Lists int
+	// M is the number of bidirectional links per node built for IndexHNSW.
+	// Defaults to 16.
+	M int
+	// EfConstruction is the size of the dynamic candidate list used while
+	// building an IndexHNSW graph. Defaults to 200.
+	EfConstruction int
+	// Quantization selects the embedding column's storage representation.
+	Quantization Quantization
+}
+
+// clause returns the vec0 index= column modifier for opts, or "" for
+// IndexFlat (vec0's default, brute-force scan).
+func (opts IndexOptions) clause() string {
+	switch opts.Kind {
+	case IndexIVF:
+		lists := opts.Lists
+		if lists <= 0 {
+			lists = 100
+		}
+		return fmt.Sprintf(" index=ivf(lists=%d)", lists)
+	case IndexHNSW:
+		m := opts.M
+		if m <= 0 {
+			m = 16
+		}
+		efConstruction := opts.EfConstruction
+		if efConstruction <= 0 {
+			efConstruction = 200
+		}
+		return fmt.Sprintf(" index=hnsw(m=%d,ef_construction=%d)", m, efConstruction)
+	default:
+		return ""
+	}
+}
+
+// vecColumnType returns the vec0 column type declaration for an embedding
+// column of dimensions components under metric, quant and index, e.g.
+// "int8[768] distance_metric=cosine index=hnsw(m=16,ef_construction=200)".
+func vecColumnType(dimensions int, metric DistanceMetric, quant Quantization, index IndexOptions) string {
+	col := fmt.Sprintf("%s[%d]", quant.vecElementType(), dimensions)
+	switch metric.resolve() {
+	case MetricCosine:
+		col += " distance_metric=cosine"
+	case MetricDot:
+		col += " distance_metric=dot"
+	}
+	col += index.clause()
+	return col
+}
+
+// similarity normalizes a raw vec0 distance for this metric into a 0..1
+// score, where 1 is most similar.
+func (m DistanceMetric) similarity(distance float64) float64 {
+	switch m.resolve() {
+	case MetricCosine:
+		// vec0 cosine distance is 1 - cosine_similarity, ranging over [0, 2].
+		return clamp01(1 - distance/2)
+	case MetricDot:
+		// vec0's dot metric returns the negative inner product as
+		// "distance" so that ascending order still means most similar;
+		// undo that and fold the [-1, 1] inner product range into [0, 1].
+		return clamp01((1 - distance) / 2)
+	default:
+		// L2 distance has no fixed upper bound, so fold it into (0, 1]
+		// instead of a linear scale.
+		return 1 / (1 + distance)
+	}
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// defaultInsertBatchSize is how many rows InsertVectors and
+// InsertVectorStream commit per transaction when the VectorDB wasn't
+// configured with WithInsertBatchSize.
+const defaultInsertBatchSize = 1000
+
+// vectorDBOptions holds NewVectorDB's optional configuration.
+type vectorDBOptions struct {
+	metric          DistanceMetric
+	index           IndexOptions
+	insertBatchSize int
+	columns         []VectorColumn
+}
+
+// VectorDBOption configures optional behavior of NewVectorDB.
+type VectorDBOption func(*vectorDBOptions)
+
+// WithDistanceMetric selects the vec0 distance metric the embedding column
+// is created with. Defaults to MetricL2.
+func WithDistanceMetric(metric DistanceMetric) VectorDBOption {
+	return func(o *vectorDBOptions) {
+		o.metric = metric
+	}
+}
+
+// WithIndexOptions selects the approximate nearest-neighbor index and
+// storage representation the embedding column is created with. Defaults to
+// an exact, full-precision flat scan.
+func WithIndexOptions(index IndexOptions) VectorDBOption {
+	return func(o *vectorDBOptions) {
+		o.index = index
+	}
+}
+
+// WithInsertBatchSize sets how many rows InsertVectors and
+// InsertVectorStream bind per transaction. Defaults to 1000.
+func WithInsertBatchSize(batchSize int) VectorDBOption {
+	return func(o *vectorDBOptions) {
+		o.insertBatchSize = batchSize
+	}
+}
+
+// WithColumns configures NewVectorDB to create one named vec0 column per
+// entry instead of the single default "embedding" column, e.g.
+// vec0(title float[768], body float[1536] distance_metric=cosine). Use
+// InsertNamed/SearchNamed instead of InsertVector/SearchSimilarVectors once
+// this is set; the dimensions argument to NewVectorDB is ignored.
+func WithColumns(columns []VectorColumn) VectorDBOption {
+	return func(o *vectorDBOptions) {
+		o.columns = columns
+	}
 }
 
 // NewVectorDB creates a new vector database instance with the given database and table name
-func NewVectorDB(ctx context.Context, db *sql.DB, tableName string, dimensions int) (*VectorDB, error) {
+func NewVectorDB(ctx context.Context, db *sql.DB, tableName string, dimensions int, opts ...VectorDBOption) (*VectorDB, error) {
+	var o vectorDBOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	columns := o.columns
+	if len(columns) == 0 {
+		columns = []VectorColumn{{
+			Name:         defaultVectorColumn,
+			Dimensions:   dimensions,
+			Metric:       o.metric,
+			Quantization: o.index.Quantization,
+		}}
+	}
+
 	vs := &VectorDB{
-		db:         db,
-		tableName:  tableName,
-		dimensions: dimensions,
+		db:              db,
+		tableName:       tableName,
+		dimensions:      dimensions,
+		metric:          o.metric.resolve(),
+		index:           o.index,
+		insertBatchSize: o.insertBatchSize,
+		columns:         make(map[string]VectorColumn, len(columns)),
 	}
 
 	if vs.tableName == "" {
 		vs.tableName = "vectors"
 	}
 
+	colDefs := make([]string, len(columns))
+	for i, c := range columns {
+		vs.columns[c.Name] = c
+		colDefs[i] = fmt.Sprintf("%s %s", c.Name, vecColumnType(c.Dimensions, c.Metric, c.Quantization, vs.index))
+	}
+
 	// Create the virtual table using the vec extension
-	query := fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING vec0(embedding float[%d])", vs.tableName, dimensions)
+	query := fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING vec0(%s)", vs.tableName, strings.Join(colDefs, ", "))
 	if _, err := vs.db.ExecContext(ctx, query); err != nil {
 		return nil, fmt.Errorf("failed to create vector table: %w", err)
 	}
@@ -151,56 +407,283 @@ func NewVectorDB(ctx context.Context, db *sql.DB, tableName string, dimensions i
 	return vs, nil
 }
 
+// float32sToBytes encodes a vector as the little-endian byte layout vec0
+// expects for a float[N] column.
+func float32sToBytes(vector []float32) []byte {
+	vectorBytes := make([]byte, len(vector)*4) // 4 bytes per float32
+	for i, f := range vector {
+		binary.LittleEndian.PutUint32(vectorBytes[i*4:], math.Float32bits(f))
+	}
+	return vectorBytes
+}
+
+// bytesToFloat32s decodes vec0's little-endian float[N] byte layout back
+// into a vector, the inverse of float32sToBytes.
+func bytesToFloat32s(data []byte) []float32 {
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return vector
+}
+
 // InsertVector inserts a vector with the given ID
 func (vs *VectorDB) InsertVector(ctx context.Context, id uint64, vector []float32) error {
-	if len(vector) != vs.dimensions {
-		return fmt.Errorf("vector dimension mismatch: expected %d, got %d", vs.dimensions, len(vector))
-	}
+	return vs.InsertNamed(ctx, defaultVectorColumn, id, vector)
+}
 
-	// Convert float32 slice to byte slice
-	vectorBytes := make([]byte, len(vector)*4) // 4 bytes per float32
-	for i, f := range vector {
-		bits := *(*uint32)(unsafe.Pointer(&f))
-		vectorBytes[i*4] = byte(bits)
-		vectorBytes[i*4+1] = byte(bits >> 8)
-		vectorBytes[i*4+2] = byte(bits >> 16)
-		vectorBytes[i*4+3] = byte(bits >> 24)
+// InsertNamed upserts vector into column for id. Unlike InsertVector's plain
+// INSERT, this first tries an UPDATE so that setting one column of a
+// multi-column (WithColumns) table doesn't clobber id's other columns; only
+// rows with no existing data for id fall back to an INSERT.
+func (vs *VectorDB) InsertNamed(ctx context.Context, column string, id uint64, vector []float32) error {
+	col, ok := vs.columns[column]
+	if !ok {
+		return fmt.Errorf("unknown vector column %q", column)
+	}
+	if len(vector) != col.Dimensions {
+		return fmt.Errorf("vector dimension mismatch for column %q: expected %d, got %d", column, col.Dimensions, len(vector))
 	}
 
-	query := fmt.Sprintf("INSERT INTO %s(rowid, embedding) VALUES (?, ?)", vs.tableName)
-	_, err := vs.db.ExecContext(ctx, query, id, vectorBytes)
+	vectorBytes := float32sToBytes(vector)
+
+	updateQuery := fmt.Sprintf("UPDATE %s SET %s = ? WHERE rowid = ?", vs.tableName, column)
+	res, err := vs.db.ExecContext(ctx, updateQuery, vectorBytes, id)
 	if err != nil {
+		return fmt.Errorf("failed to update vector: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf("INSERT INTO %s(rowid, %s) VALUES (?, ?)", vs.tableName, column)
+	if _, err := vs.db.ExecContext(ctx, insertQuery, id, vectorBytes); err != nil {
 		return fmt.Errorf("failed to insert vector: %w", err)
 	}
 
 	return nil
 }
 
+// VectorRecord is one row streamed to InsertVectorStream.
+type VectorRecord struct {
+	ID     uint64
+	Vector []float32
+}
+
+// InsertVectors bulk-inserts ids and vectors, committing a transaction
+// every insertBatchSize rows (see WithInsertBatchSize, default 1000)
+// instead of one ExecContext per row. A batch that fails is rolled back
+// without affecting batches already committed.
+func (vs *VectorDB) InsertVectors(ctx context.Context, ids []uint64, vectors [][]float32) error {
+	return vs.InsertVectorsNamed(ctx, defaultVectorColumn, ids, vectors)
+}
+
+// InsertVectorsNamed is InsertVectors' multi-column (WithColumns)
+// counterpart, bulk-inserting into column.
+func (vs *VectorDB) InsertVectorsNamed(ctx context.Context, column string, ids []uint64, vectors [][]float32) error {
+	if len(ids) != len(vectors) {
+		return fmt.Errorf("ids/vectors length mismatch: %d ids, %d vectors", len(ids), len(vectors))
+	}
+	if _, ok := vs.columns[column]; !ok {
+		return fmt.Errorf("unknown vector column %q", column)
+	}
+
+	batchSize := vs.insertBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultInsertBatchSize
+	}
+
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		if err := vs.insertVectorBatch(ctx, column, ids[start:end], vectors[start:end]); err != nil {
+			return fmt.Errorf("failed to insert vectors [%d:%d]: %w", start, end, err)
+		}
+	}
+
+	return nil
+}
+
+// InsertVectorStream drains records, committing a batch (see
+// WithInsertBatchSize, default 1000) at a time, so arbitrarily large
+// corpora can be ingested without buffering them all in memory. Returns
+// after records is closed and any final partial batch is committed, or
+// immediately on the first batch error or ctx cancellation.
+func (vs *VectorDB) InsertVectorStream(ctx context.Context, records <-chan VectorRecord) error {
+	return vs.InsertVectorStreamNamed(ctx, defaultVectorColumn, records)
+}
+
+// InsertVectorStreamNamed is InsertVectorStream's multi-column (WithColumns)
+// counterpart, streaming records into column.
+func (vs *VectorDB) InsertVectorStreamNamed(ctx context.Context, column string, records <-chan VectorRecord) error {
+	if _, ok := vs.columns[column]; !ok {
+		return fmt.Errorf("unknown vector column %q", column)
+	}
+
+	batchSize := vs.insertBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultInsertBatchSize
+	}
+
+	ids := make([]uint64, 0, batchSize)
+	vectors := make([][]float32, 0, batchSize)
+
+	flush := func() error {
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := vs.insertVectorBatch(ctx, column, ids, vectors); err != nil {
+			return err
+		}
+		ids = ids[:0]
+		vectors = vectors[:0]
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case rec, ok := <-records:
+			if !ok {
+				return flush()
+			}
+			ids = append(ids, rec.ID)
+			vectors = append(vectors, rec.Vector)
+			if len(ids) >= batchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// insertVectorBatch inserts ids/vectors into column within a single
+// transaction, using a prepared statement bound once per row.
+func (vs *VectorDB) insertVectorBatch(ctx context.Context, column string, ids []uint64, vectors [][]float32) error {
+	dimensions := vs.columns[column].Dimensions
+
+	tx, err := vs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s(rowid, %s) VALUES (?, ?)", vs.tableName, column))
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, id := range ids {
+		if len(vectors[i]) != dimensions {
+			return fmt.Errorf("vector dimension mismatch at index %d: expected %d, got %d", i, dimensions, len(vectors[i]))
+		}
+		if _, err := stmt.ExecContext(ctx, id, float32sToBytes(vectors[i])); err != nil {
+			return fmt.Errorf("failed to insert vector %d: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SearchOptions configures VectorDB.SearchSimilarVectors.
+type SearchOptions struct {
+	// TopK is the maximum number of results to return. Defaults to 10.
+	TopK int
+	// Metric picks the formula used to normalize each result's raw vec0
+	// distance into VectorResult.Score. Defaults to the VectorDB's
+	// configured metric; only set this to something else if comparing
+	// scores computed a different way than the table itself was built for.
+	Metric DistanceMetric
+	// Threshold, when greater than zero, drops results whose normalized
+	// Score falls below it.
+	Threshold float64
+	// Filter is an optional raw SQL predicate, validated the same way as
+	// ExecuteBatch's WHERE clauses, ANDed into the KNN query to prune
+	// candidates before the match. Only rowid is filterable today, since
+	// VectorDB has no metadata columns of its own.
+	Filter string
+	// IncludeVector populates VectorResult.Vector with the decoded stored
+	// embedding. Left off by default to avoid the decode cost.
+	IncludeVector bool
+	// Ef tunes the size of the dynamic candidate list an IndexHNSW table's
+	// search walks, trading recall for latency. Ignored on other index
+	// kinds. Zero uses vec0's own default.
+	Ef int
+	// Probes tunes the number of inverted-file partitions an IndexIVF
+	// table's search visits, trading recall for latency. Ignored on other
+	// index kinds. Zero uses vec0's own default.
+	Probes int
+}
+
 // SearchSimilarVectors searches for vectors similar to the query vector
-func (vs *VectorDB) SearchSimilarVectors(ctx context.Context, queryVector []float32, limit int) ([]VectorResult, error) {
-	if len(queryVector) != vs.dimensions {
-		return nil, fmt.Errorf("query vector dimension mismatch: expected %d, got %d", vs.dimensions, len(queryVector))
+func (vs *VectorDB) SearchSimilarVectors(ctx context.Context, queryVector []float32, opts SearchOptions) ([]VectorResult, error) {
+	return vs.SearchNamed(ctx, defaultVectorColumn, queryVector, opts)
+}
+
+// SearchNamed runs a KNN search against column, the multi-column
+// (WithColumns) counterpart to SearchSimilarVectors.
+func (vs *VectorDB) SearchNamed(ctx context.Context, column string, queryVector []float32, opts SearchOptions) ([]VectorResult, error) {
+	col, ok := vs.columns[column]
+	if !ok {
+		return nil, fmt.Errorf("unknown vector column %q", column)
+	}
+	if len(queryVector) != col.Dimensions {
+		return nil, fmt.Errorf("query vector dimension mismatch for column %q: expected %d, got %d", column, col.Dimensions, len(queryVector))
 	}
 
-	// Convert query vector to bytes
-	queryBytes := make([]byte, len(queryVector)*4)
-	for i, f := range queryVector {
-		bits := *(*uint32)(unsafe.Pointer(&f))
-		queryBytes[i*4] = byte(bits)
-		queryBytes[i*4+1] = byte(bits >> 8)
-		queryBytes[i*4+2] = byte(bits >> 16)
-		queryBytes[i*4+3] = byte(bits >> 24)
+	topK := opts.TopK
+	if topK <= 0 {
+		topK = 10
+	}
+	metric := opts.Metric
+	if metric == MetricDefault {
+		metric = col.Metric.resolve()
+	}
+
+	var predicates []string
+	args := []any{float32sToBytes(queryVector)}
+
+	if opts.Ef > 0 {
+		predicates = append(predicates, "ef_search = ?")
+		args = append(args, opts.Ef)
+	}
+	if opts.Probes > 0 {
+		predicates = append(predicates, "probes = ?")
+		args = append(args, opts.Probes)
+	}
+	if opts.Filter != "" {
+		if err := validateWhereClause(opts.Filter, map[string]bool{"rowid": true}); err != nil {
+			return nil, fmt.Errorf("invalid search filter: %w", err)
+		}
+		predicates = append(predicates, opts.Filter)
+	}
+
+	whereClause := ""
+	if len(predicates) > 0 {
+		whereClause = " AND " + strings.Join(predicates, " AND ")
+	}
+	args = append(args, topK)
+
+	selectCols := "rowid, distance"
+	if opts.IncludeVector {
+		selectCols = fmt.Sprintf("rowid, distance, %s", column)
 	}
 
 	query := fmt.Sprintf(`
-		SELECT rowid, distance 
-		FROM %s 
-		WHERE embedding MATCH ? 
-		ORDER BY distance 
+		SELECT %s
+		FROM %s
+		WHERE %s MATCH ?%s
+		ORDER BY distance
 		LIMIT ?
-	`, vs.tableName)
+	`, selectCols, vs.tableName, column, whereClause)
 
-	rows, err := vs.db.QueryContext(ctx, query, queryBytes, limit)
+	rows, err := vs.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search vectors: %w", err)
 	}
@@ -210,14 +693,29 @@ func (vs *VectorDB) SearchSimilarVectors(ctx context.Context, queryVector []floa
 	for rows.Next() {
 		var id int64
 		var distance float64
-		if err := rows.Scan(&id, &distance); err != nil {
+		var embeddingBytes []byte
+
+		dest := []any{&id, &distance}
+		if opts.IncludeVector {
+			dest = append(dest, &embeddingBytes)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+
+		score := metric.similarity(distance)
+		if opts.Threshold > 0 && score < opts.Threshold {
 			continue
 		}
 
-		results = append(results, VectorResult{
-			ID:       id,
-			Distance: distance,
-		})
+		result := VectorResult{ID: id, Distance: distance, Score: score}
+		if opts.IncludeVector {
+			result.Vector = bytesToFloat32s(embeddingBytes)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return results, nil
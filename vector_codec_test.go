@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"math"
+	"testing"
+)
+
+// TestFloat32sBytesRoundTrip guards float32sToBytes/bytesToFloat32s' shared
+// little-endian layout: a silent byte-order or width regression here would
+// corrupt every vector written to vec0 without surfacing as a build or vet
+// failure.
+func TestFloat32sBytesRoundTrip(t *testing.T) {
+	vectors := [][]float32{
+		{},
+		{0},
+		{1, -1, 0.5, -0.5},
+		{float32(math.MaxFloat32), -float32(math.MaxFloat32)},
+		{float32(math.Inf(1)), float32(math.Inf(-1))},
+		{float32(math.NaN())},
+	}
+
+	for i, v := range vectors {
+		got := bytesToFloat32s(float32sToBytes(v))
+		if len(got) != len(v) {
+			t.Fatalf("case %d: expected length %d, got %d", i, len(v), len(got))
+		}
+		for j := range v {
+			want, g := v[j], got[j]
+			if math.IsNaN(float64(want)) {
+				if !math.IsNaN(float64(g)) {
+					t.Fatalf("case %d[%d]: expected NaN, got %v", i, j, g)
+				}
+				continue
+			}
+			if want != g {
+				t.Fatalf("case %d[%d]: expected %v, got %v", i, j, want, g)
+			}
+		}
+	}
+}
+
+func TestFloat32sToBytesLength(t *testing.T) {
+	b := float32sToBytes([]float32{1, 2, 3})
+	if len(b) != 12 {
+		t.Fatalf("expected 12 bytes for 3 float32s, got %d", len(b))
+	}
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// newTestVectorDB builds a VectorDB with the given named columns without
+// touching the database - InsertNamed/SearchNamed validate column name and
+// dimensions before issuing any SQL, so these tests exercise that validation
+// directly. A real vec0-backed VectorDB needs the (cgo-loaded) vec extension,
+// which isn't available in this test environment.
+func newTestVectorDB(columns ...VectorColumn) *VectorDB {
+	vs := &VectorDB{
+		tableName: "vectors",
+		columns:   make(map[string]VectorColumn, len(columns)),
+	}
+	for _, c := range columns {
+		vs.columns[c.Name] = c
+	}
+	return vs
+}
+
+func TestInsertNamedRejectsUnknownColumn(t *testing.T) {
+	vs := newTestVectorDB(VectorColumn{Name: "title", Dimensions: 4})
+	err := vs.InsertNamed(context.Background(), "body", 1, []float32{1, 2, 3, 4})
+	if err == nil || !strings.Contains(err.Error(), `unknown vector column "body"`) {
+		t.Fatalf("expected unknown column error, got %v", err)
+	}
+}
+
+func TestInsertNamedRejectsDimensionMismatch(t *testing.T) {
+	vs := newTestVectorDB(VectorColumn{Name: "title", Dimensions: 4})
+	err := vs.InsertNamed(context.Background(), "title", 1, []float32{1, 2, 3})
+	if err == nil || !strings.Contains(err.Error(), "dimension mismatch") {
+		t.Fatalf("expected dimension mismatch error, got %v", err)
+	}
+}
+
+func TestSearchNamedRejectsUnknownColumn(t *testing.T) {
+	vs := newTestVectorDB(VectorColumn{Name: "title", Dimensions: 4})
+	_, err := vs.SearchNamed(context.Background(), "body", []float32{1, 2, 3, 4}, SearchOptions{})
+	if err == nil || !strings.Contains(err.Error(), `unknown vector column "body"`) {
+		t.Fatalf("expected unknown column error, got %v", err)
+	}
+}
+
+func TestSearchNamedRejectsDimensionMismatch(t *testing.T) {
+	vs := newTestVectorDB(VectorColumn{Name: "title", Dimensions: 4})
+	_, err := vs.SearchNamed(context.Background(), "title", []float32{1, 2, 3}, SearchOptions{})
+	if err == nil || !strings.Contains(err.Error(), "dimension mismatch") {
+		t.Fatalf("expected dimension mismatch error, got %v", err)
+	}
+}
+
+// TestInsertVectorsNamedRejectsUnknownColumn verifies the bulk-insert path
+// validates the target column before opening any transaction.
+func TestInsertVectorsNamedRejectsUnknownColumn(t *testing.T) {
+	vs := newTestVectorDB(VectorColumn{Name: "title", Dimensions: 4})
+	err := vs.InsertVectorsNamed(context.Background(), "body", []uint64{1}, [][]float32{{1, 2, 3, 4}})
+	if err == nil || !strings.Contains(err.Error(), `unknown vector column "body"`) {
+		t.Fatalf("expected unknown column error, got %v", err)
+	}
+}
+
+// TestDistanceMetricSimilarity covers the normalization formula for each
+// metric, including the clamping at the edges of their raw distance ranges.
+// TestIndexOptionsClause covers the vec0 index= modifier generated for each
+// IndexKind, including the defaults applied when Lists/M/EfConstruction are
+// left unset.
+func TestIndexOptionsClause(t *testing.T) {
+	tests := []struct {
+		name string
+		opts IndexOptions
+		want string
+	}{
+		{"flat is the zero value", IndexOptions{}, ""},
+		{"ivf defaults lists to 100", IndexOptions{Kind: IndexIVF}, " index=ivf(lists=100)"},
+		{"ivf honors explicit lists", IndexOptions{Kind: IndexIVF, Lists: 256}, " index=ivf(lists=256)"},
+		{"hnsw defaults m and ef_construction", IndexOptions{Kind: IndexHNSW}, " index=hnsw(m=16,ef_construction=200)"},
+		{"hnsw honors explicit m and ef_construction", IndexOptions{Kind: IndexHNSW, M: 32, EfConstruction: 400}, " index=hnsw(m=32,ef_construction=400)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.clause(); got != tt.want {
+				t.Fatalf("clause() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceMetricSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		metric   DistanceMetric
+		distance float64
+		want     float64
+	}{
+		{"l2 zero distance", MetricL2, 0, 1},
+		{"l2 unit distance", MetricL2, 1, 0.5},
+		{"default resolves to l2", MetricDefault, 1, 0.5},
+		{"cosine identical", MetricCosine, 0, 1},
+		{"cosine orthogonal", MetricCosine, 1, 0.5},
+		{"cosine opposite", MetricCosine, 2, 0},
+		{"cosine clamps above range", MetricCosine, 3, 0},
+		{"dot matching", MetricDot, -1, 1},
+		{"dot orthogonal", MetricDot, 0, 0.5},
+		{"dot opposite", MetricDot, 1, 0},
+		{"dot clamps below range", MetricDot, -2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metric.similarity(tt.distance); got != tt.want {
+				t.Fatalf("similarity(%v) = %v, want %v", tt.distance, got, tt.want)
+			}
+		})
+	}
+}